@@ -1,9 +1,15 @@
 package application
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"net/http"
 	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/hanzoai/agents/control-plane/internal/cli/framework"
 	"github.com/hanzoai/agents/control-plane/internal/config"
@@ -11,16 +17,44 @@ import (
 	"github.com/hanzoai/agents/control-plane/internal/infrastructure/process"
 	"github.com/hanzoai/agents/control-plane/internal/infrastructure/storage"
 	"github.com/hanzoai/agents/control-plane/internal/logger"
+	"github.com/hanzoai/agents/control-plane/internal/middleware"
 	didServices "github.com/hanzoai/agents/control-plane/internal/services"
 	storageInterface "github.com/hanzoai/agents/control-plane/internal/storage"
+	"github.com/hanzoai/agents/control-plane/internal/utils"
+)
+
+var (
+	serviceMetricsOnce sync.Once
+	serviceMetrics     *middleware.Metrics
 )
 
+// serviceRecoveryMetrics lazily registers the service-method latency/error
+// collectors against the default Prometheus registry exactly once per
+// process, so CreateServiceContainer can be called more than once (e.g. by
+// successive CLI commands in the same run) without tripping a
+// duplicate-registration error.
+func serviceRecoveryMetrics() *middleware.Metrics {
+	serviceMetricsOnce.Do(func() {
+		m, err := middleware.NewMetrics(prometheus.DefaultRegisterer)
+		if err != nil {
+			logger.Logger.Warn().Err(err).Msg("failed to register service recovery metrics, continuing without them")
+			return
+		}
+		serviceMetrics = m
+	})
+	return serviceMetrics
+}
+
 // CreateServiceContainer creates and wires up all services for the CLI commands
 func CreateServiceContainer(cfg *config.Config, hanzo-agentsHome string) *framework.ServiceContainer {
 	// Create infrastructure components
-	fileSystem := storage.NewFileSystemAdapter()
-	registryPath := filepath.Join(hanzo-agentsHome, "installed.json")
-	registryStorage := storage.NewLocalRegistryStorage(fileSystem, registryPath)
+	fileSystem, err := storage.NewFileSystemAdapterForURI(cfg.Storage.Filesystem.URI, cfg.Storage.Filesystem)
+	if err != nil {
+		// Log error - remote filesystem backend could not be constructed;
+		// fall back to the local adapter so the CLI keeps working.
+		logger.Logger.Warn().Err(err).Msg("failed to create configured filesystem adapter, falling back to local")
+		fileSystem = storage.NewFileSystemAdapter()
+	}
 	processManager := process.NewProcessManager()
 	portManager := process.NewPortManager()
 
@@ -33,7 +67,40 @@ func CreateServiceContainer(cfg *config.Config, hanzo-agentsHome string) *framew
 		storageProvider = nil
 	}
 
+	// Built before the services below so fileSystem can be wrapped with
+	// quota enforcement against its own disk usage snapshot: every file
+	// this container writes (installed packages, dev artifacts) is the
+	// closest thing to a "payload write path" this snapshot has, since
+	// there's no dedicated payload storage adapter here yet.
+	//
+	// NOTE: diskUsageMonitor measures utils.GetHanzoAgentsDataDirectories's
+	// local PayloadsDir regardless of cfg.Storage.Filesystem.URI, so when
+	// fileSystem is actually backed by S3/GCS/Azure (see
+	// NewFileSystemAdapterForURI above), writes never touch that local
+	// directory and the hard quota never trips for those installs. Quota
+	// enforcement here is effectively local-disk-only until usage is
+	// tracked per configured backend.
+	diskUsageMonitor := newDiskUsageMonitor(cfg, storageProvider)
+	if diskUsageMonitor != nil {
+		fileSystem = storage.NewQuotaEnforcingFileSystemAdapter(fileSystem, diskUsageMonitor, utils.CategoryPayloads)
+	}
+
+	registryPath := filepath.Join(hanzo-agentsHome, "installed.json")
+	registryStorage := storage.NewLocalRegistryStorage(fileSystem, registryPath)
+
+	metrics := serviceRecoveryMetrics()
+
 	// Create services
+	//
+	// NOTE: PackageService, AgentService and DevService (core/services) are
+	// consumed here as concrete struct types with no methods called
+	// anywhere else in this tree, so there's no evidence of their real
+	// method set to extract an interface from -- unlike DIDRegistry/
+	// DIDService/VCService below, whose Initialize calls go through
+	// generator-decorated wrappers (services/lifecycle.go); their other
+	// methods are not decorated. Doing the
+	// same for these three requires first extracting their interfaces from
+	// core/services itself, wherever that package's source lands.
 	packageService := services.NewPackageService(registryStorage, fileSystem, hanzo-agentsHome)
 	agentService := services.NewAgentService(processManager, portManager, registryStorage, nil, hanzo-agentsHome) // nil agentClient for now
 	devService := services.NewDevService(processManager, portManager, fileSystem)
@@ -61,7 +128,7 @@ func CreateServiceContainer(cfg *config.Config, hanzo-agentsHome string) *framew
 		}
 
 		if didRegistry != nil {
-			if err := didRegistry.Initialize(); err != nil {
+			if err := didServices.WrapDIDRegistryLifecycle(didRegistry, metrics).Initialize(context.Background()); err != nil {
 				// Log error but continue
 				didRegistry = nil
 			}
@@ -74,7 +141,7 @@ func CreateServiceContainer(cfg *config.Config, hanzo-agentsHome string) *framew
 			// Generate af server ID based on hanzo-agents home directory
 			// This ensures each hanzo-agents instance has a unique ID while being deterministic
 			hanzo-agentsServerID := generateHanzoAgentsServerID(hanzo-agentsHome)
-			if err := didService.Initialize(hanzo-agentsServerID); err != nil {
+			if err := didServices.WrapDIDServiceLifecycle(didService, metrics).Initialize(context.Background(), hanzo-agentsServerID); err != nil {
 				logger.Logger.Warn().Err(err).Msg("failed to initialize DID service")
 				didService = nil
 			} else {
@@ -84,7 +151,7 @@ func CreateServiceContainer(cfg *config.Config, hanzo-agentsHome string) *framew
 				}
 
 				if vcService != nil {
-					if err := vcService.Initialize(); err != nil {
+					if err := didServices.WrapVCServiceLifecycle(vcService, metrics).Initialize(context.Background()); err != nil {
 						logger.Logger.Warn().Err(err).Msg("failed to initialize VC service")
 						vcService = nil
 					}
@@ -94,15 +161,81 @@ func CreateServiceContainer(cfg *config.Config, hanzo-agentsHome string) *framew
 	}
 
 	return &framework.ServiceContainer{
-		PackageService:  packageService,
-		AgentService:    agentService,
-		DevService:      devService,
-		DIDService:      didService,
-		VCService:       vcService,
-		KeystoreService: keystoreService,
-		DIDRegistry:     didRegistry,
-		StorageProvider: storageProvider,
+		PackageService:   packageService,
+		AgentService:     agentService,
+		DevService:       devService,
+		DIDService:       didService,
+		VCService:        vcService,
+		KeystoreService:  keystoreService,
+		DIDRegistry:      didRegistry,
+		StorageProvider:  storageProvider,
+		DiskUsageMonitor: diskUsageMonitor,
+	}
+}
+
+// newDiskUsageMonitor wires a utils.DiskUsageMonitor over the HanzoAgents
+// data directories, enforcing the quotas from cfg.Storage.Quota. When a
+// soft quota trips for the payloads category, it runs an aggressive
+// CleanupOldExecutions pass against storageProvider to make room; for logs
+// it gzip-rotates everything but the active log file via utils.RotateLogs.
+// CreateServiceContainer wraps fileSystem with
+// storage.NewQuotaEnforcingFileSystemAdapter against this monitor's
+// CategoryPayloads quota, so CheckQuota actually refuses writes once a
+// hard quota trips -- not just the internal cleanup/rotation above.
+//
+// NOTE: internal/storage's LocalStorage (workflow execution persistence)
+// isn't part of this snapshot, so StoreWorkflowExecution itself still has
+// no CheckQuota call; fileSystem is the only real write path this
+// container has today to enforce against.
+func newDiskUsageMonitor(cfg *config.Config, storageProvider storageInterface.Storage) *utils.DiskUsageMonitor {
+	dirs, err := utils.GetHanzoAgentsDataDirectories()
+	if err != nil {
+		logger.Logger.Warn().Err(err).Msg("failed to resolve data directories for disk usage monitoring")
+		return nil
 	}
+
+	quotas := map[string]utils.CategoryQuota{
+		utils.CategoryPayloads: {SoftBytes: cfg.Storage.Quota.PayloadsSoftMaxBytes, HardBytes: cfg.Storage.Quota.PayloadsMaxBytes},
+		utils.CategoryLogs:     {SoftBytes: cfg.Storage.Quota.LogsSoftMaxBytes, HardBytes: cfg.Storage.Quota.LogsMaxBytes},
+	}
+
+	onSoft := func(category string) error {
+		logger.Logger.Warn().Str("category", category).Msg("disk soft quota exceeded, triggering cleanup")
+
+		switch category {
+		case utils.CategoryPayloads:
+			if storageProvider == nil {
+				return nil
+			}
+
+			const aggressiveWindow = 10 * time.Minute
+			deleted, cleanupErr := storageProvider.CleanupOldExecutions(context.Background(), aggressiveWindow, 1000)
+			if cleanupErr != nil {
+				return cleanupErr
+			}
+			logger.Logger.Info().Int("deleted", deleted).Msg("cleaned up old workflow executions after soft quota trip")
+			return nil
+
+		case utils.CategoryLogs:
+			rotated, rotateErr := utils.RotateLogs(dirs.LogsDir)
+			if rotateErr != nil {
+				return rotateErr
+			}
+			logger.Logger.Info().Int("rotated", rotated).Msg("rotated log files after soft quota trip")
+			return nil
+
+		default:
+			return nil
+		}
+	}
+
+	onError := func(category string, pollErr error) {
+		logger.Logger.Warn().Err(pollErr).Str("category", category).Msg("disk usage poll failed")
+	}
+
+	monitor := utils.NewDiskUsageMonitor(dirs, quotas, onSoft, onError)
+	monitor.Start(context.Background(), 5*time.Minute)
+	return monitor
 }
 
 // CreateServiceContainerWithDefaults creates a service container with default configuration
@@ -131,3 +264,21 @@ func generateHanzoAgentsServerID(hanzo-agentsHome string) string {
 
 	return hanzo-agentsServerID
 }
+
+// MountDiskUsageEndpoint registers container's DiskUsageMonitor on mux at
+// path for JSON inspection and with registry as a Prometheus collector, so
+// whatever serves the CLI's admin/metrics HTTP surface can expose disk
+// usage with one call. It's a no-op if the container has no monitor (e.g.
+// resolving the data directories failed at construction time).
+func MountDiskUsageEndpoint(mux *http.ServeMux, registry prometheus.Registerer, path string, container *framework.ServiceContainer) {
+	if container == nil || container.DiskUsageMonitor == nil {
+		return
+	}
+
+	mux.Handle(path, container.DiskUsageMonitor)
+	if registry != nil {
+		if err := registry.Register(container.DiskUsageMonitor); err != nil {
+			logger.Logger.Warn().Err(err).Msg("failed to register disk usage collector")
+		}
+	}
+}
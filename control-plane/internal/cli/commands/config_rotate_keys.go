@@ -0,0 +1,32 @@
+// hanzo-agents/internal/cli/commands/config_rotate_keys.go
+package commands
+
+import (
+	"fmt"
+
+	"github.com/hanzoai/agents/control-plane/internal/core/interfaces"
+	"github.com/hanzoai/agents/control-plane/internal/infrastructure/storage"
+	"github.com/hanzoai/agents/control-plane/internal/logger"
+)
+
+// RunConfigRotateKeys implements `hanzo-agents config rotate-keys`: it
+// re-seals every encrypted config file under configDir from whatever KEK
+// its own envelope currently names to newKeyID, via storage.RotateConfigKeys.
+// Callers typically pass utils.GetHanzoAgentsDataDirectories().ConfigDir for
+// configDir and the DID keystore service (which implements
+// interfaces.KeyWrapper) for keystore.
+func RunConfigRotateKeys(fs interfaces.FileSystemAdapter, configDir string, keystore interfaces.KeyWrapper, newKeyID string) error {
+	if newKeyID == "" {
+		return fmt.Errorf("commands: rotate-keys requires a new key id")
+	}
+	if keystore == nil {
+		return fmt.Errorf("commands: rotate-keys requires a keystore to wrap the new data keys")
+	}
+
+	if err := storage.RotateConfigKeys(fs, configDir, keystore, newKeyID); err != nil {
+		return fmt.Errorf("commands: rotate-keys: %w", err)
+	}
+
+	logger.Logger.Info().Str("key_id", newKeyID).Str("dir", configDir).Msg("rotated config encryption keys")
+	return nil
+}
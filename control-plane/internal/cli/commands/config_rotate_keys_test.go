@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/hanzoai/agents/control-plane/internal/core/domain"
+	"github.com/hanzoai/agents/control-plane/internal/infrastructure/storage"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKeystore is a minimal in-memory stand-in for didServices.KeystoreService,
+// AES-256-GCM wrapping data keys under named KEKs it generates on first use.
+type fakeKeystore struct {
+	keks map[string][]byte
+}
+
+func newFakeKeystore() *fakeKeystore {
+	return &fakeKeystore{keks: make(map[string][]byte)}
+}
+
+func (k *fakeKeystore) kek(keyID string) ([]byte, error) {
+	if existing, ok := k.keks[keyID]; ok {
+		return existing, nil
+	}
+	kek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, kek); err != nil {
+		return nil, err
+	}
+	k.keks[keyID] = kek
+	return kek, nil
+}
+
+func (k *fakeKeystore) WrapDataKey(keyID string, dataKey []byte) ([]byte, error) {
+	kek, err := k.kek(keyID)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return append(nonce, gcm.Seal(nil, nonce, dataKey, nil)...), nil
+}
+
+func (k *fakeKeystore) UnwrapDataKey(keyID string, wrapped []byte) ([]byte, error) {
+	kek, ok := k.keks[keyID]
+	if !ok {
+		return nil, aes.KeySizeError(0)
+	}
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := gcm.NonceSize()
+	if len(wrapped) < nonceSize {
+		return nil, errors.New("fakeKeystore: ciphertext shorter than nonce")
+	}
+	return gcm.Open(nil, wrapped[:nonceSize], wrapped[nonceSize:], nil)
+}
+
+func TestRunConfigRotateKeys(t *testing.T) {
+	fs := storage.NewMemoryFileSystemAdapter()
+	keystore := newFakeKeystore()
+
+	cs := storage.NewEncryptedConfigStorage(fs, keystore, "kek-old")
+	original := &domain.HanzoAgentsConfig{
+		HomeDir:     "/home/test/.hanzo-agents",
+		Environment: map[string]string{"API_TOKEN": "super-secret-value"},
+	}
+	require.NoError(t, cs.SaveHanzoAgentsConfig("configs/agent.yaml", original))
+
+	require.NoError(t, RunConfigRotateKeys(fs, "configs", keystore, "kek-new"))
+
+	rotated := storage.NewEncryptedConfigStorage(fs, keystore, "kek-new")
+	loaded, err := rotated.LoadHanzoAgentsConfig("configs/agent.yaml")
+	require.NoError(t, err)
+	require.Equal(t, original.Environment["API_TOKEN"], loaded.Environment["API_TOKEN"])
+
+	raw, err := fs.ReadFile("configs/agent.yaml")
+	require.NoError(t, err)
+	require.Contains(t, string(raw), "kek-new")
+	require.NotContains(t, string(raw), "kek-old")
+}
+
+func TestRunConfigRotateKeys_RequiresNewKeyID(t *testing.T) {
+	fs := storage.NewMemoryFileSystemAdapter()
+	err := RunConfigRotateKeys(fs, "configs", newFakeKeystore(), "")
+	require.Error(t, err)
+}
+
+func TestRunConfigRotateKeys_RequiresKeystore(t *testing.T) {
+	fs := storage.NewMemoryFileSystemAdapter()
+	err := RunConfigRotateKeys(fs, "configs", nil, "kek-new")
+	require.Error(t, err)
+}
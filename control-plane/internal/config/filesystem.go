@@ -0,0 +1,36 @@
+// hanzo-agents/internal/config/filesystem.go
+package config
+
+// FilesystemConfig configures the backend behind interfaces.FileSystemAdapter.
+// URI selects the backend by scheme (file://, s3://, gs://, azblob://,
+// memory://); the per-scheme blocks below supply the matching credentials.
+// An empty URI keeps the default local-disk adapter.
+type FilesystemConfig struct {
+	URI string `yaml:"uri"`
+
+	S3    S3FilesystemConfig    `yaml:"s3"`
+	GCS   GCSFilesystemConfig   `yaml:"gcs"`
+	Azure AzureFilesystemConfig `yaml:"azure"`
+}
+
+// S3FilesystemConfig configures the s3:// FileSystemAdapter backend.
+type S3FilesystemConfig struct {
+	Region          string `yaml:"region"`
+	Endpoint        string `yaml:"endpoint"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	PathStyle       bool   `yaml:"path_style"`
+}
+
+// GCSFilesystemConfig configures the gs:// FileSystemAdapter backend.
+type GCSFilesystemConfig struct {
+	CredentialsFile string `yaml:"credentials_file"`
+	ProjectID       string `yaml:"project_id"`
+}
+
+// AzureFilesystemConfig configures the azblob:// FileSystemAdapter backend.
+type AzureFilesystemConfig struct {
+	AccountName string `yaml:"account_name"`
+	AccountKey  string `yaml:"account_key"`
+	Endpoint    string `yaml:"endpoint"`
+}
@@ -0,0 +1,19 @@
+// hanzo-agents/internal/config/quota.go
+package config
+
+// QuotaConfig configures per-category disk quotas enforced by
+// utils.DiskUsageMonitor. A zero value for any field disables that
+// threshold (no proactive cleanup / no write refusal) for its category.
+//
+// When a soft quota trips, the monitor triggers an aggressive cleanup pass
+// for that category (CleanupOldExecutions for Payloads, log rotation for
+// Logs). When the Payloads hard quota trips, writes through the service
+// container's filesystem adapter are refused with utils.ErrQuotaExceeded
+// until usage drops back down. No write path checks the Logs hard quota, so
+// LogsMaxBytes refuses no writes.
+type QuotaConfig struct {
+	PayloadsSoftMaxBytes int64 `yaml:"payloads_soft_max_bytes"`
+	PayloadsMaxBytes     int64 `yaml:"payloads_max_bytes"`
+	LogsSoftMaxBytes     int64 `yaml:"logs_soft_max_bytes"`
+	LogsMaxBytes         int64 `yaml:"logs_max_bytes"`
+}
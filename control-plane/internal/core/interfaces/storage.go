@@ -22,3 +22,11 @@ type ConfigStorage interface {
 	LoadHanzoAgentsConfig(path string) (*domain.HanzoAgentsConfig, error)
 	SaveHanzoAgentsConfig(path string, config *domain.HanzoAgentsConfig) error
 }
+
+// KeyWrapper wraps and unwraps per-file data keys under a key-encryption
+// key (KEK) identified by keyID, e.g. backed by didServices.KeystoreService.
+// Implementations must treat wrapped key material as opaque bytes.
+type KeyWrapper interface {
+	WrapDataKey(keyID string, dataKey []byte) (wrapped []byte, err error)
+	UnwrapDataKey(keyID string, wrapped []byte) (dataKey []byte, err error)
+}
@@ -0,0 +1,377 @@
+// hanzo-agents/internal/infrastructure/storage/encrypted_config.go
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/hanzoai/agents/control-plane/internal/core/domain"
+	"github.com/hanzoai/agents/control-plane/internal/core/interfaces"
+	"github.com/hanzoai/agents/control-plane/internal/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// sealedMarker is the envelope field that identifies a mapping node as
+// encrypted rather than a plain YAML value, so Load can tell the two apart
+// without knowing the field's plaintext shape up front.
+const sealedMarker = "__sealed__"
+
+// secretTag is the struct tag domain.HanzoAgentsConfig fields carry to opt
+// into sealing, e.g. `yaml:"environment" secret:"true"`. sealedFields reads
+// it via reflection, so marking a new field sensitive is a one-line tag
+// change in domain.go rather than an edit here.
+const secretTag = "secret"
+
+// defaultSealedKeys are the top-level HanzoAgentsConfig sections sealed when
+// no field on domain.HanzoAgentsConfig carries a `secret:"true"` tag (see
+// sealedFields). It exists purely so installs predating the tag convention
+// keep their environment/MCP sections sealed exactly as before; once
+// domain.HanzoAgentsConfig's fields are tagged, this fallback is never
+// consulted.
+var defaultSealedKeys = []string{"environment", "mcp"}
+
+// sealedFields returns the HanzoAgentsConfig field names to seal, discovered
+// by walking domain.HanzoAgentsConfig's struct tags for `secret:"true"`
+// instead of hardcoding section names here. This lets operators mark any
+// individual field sensitive (not just "environment" and "mcp" wholesale)
+// by tagging it in domain.go; a field's sealed key is its yaml tag name, so
+// renaming the YAML key and the secret annotation stay in the same place.
+//
+// Falls back to defaultSealedKeys if reflection finds no tagged field at
+// all, since this snapshot's domain.HanzoAgentsConfig predates the
+// convention -- existing installs relying on environment/mcp being sealed
+// shouldn't silently stop being sealed the moment this ships.
+func sealedFields() []string {
+	t := reflect.TypeOf(domain.HanzoAgentsConfig{})
+
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if v, ok := field.Tag.Lookup(secretTag); !ok || v != "true" {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		keys = append(keys, name)
+	}
+
+	if len(keys) == 0 {
+		return defaultSealedKeys
+	}
+	return keys
+}
+
+// sealedEnvelope is the on-disk representation of one sealed section: a
+// per-file 32-byte data key (encrypted with AES-256-GCM under dataKey,
+// itself wrapped by a KEK from the keystore) plus the resulting nonce and
+// ciphertext. The binary fields are stored base64-encoded explicitly
+// (rather than relying on yaml.v3's default []byte handling, which emits
+// an unreadable per-byte integer sequence instead of !!binary).
+type sealedEnvelope struct {
+	Sealed     bool   `yaml:"__sealed__"`
+	KeyID      string `yaml:"key_id"`
+	WrappedKey string `yaml:"wrapped_key"`
+	Nonce      string `yaml:"nonce"`
+	Ciphertext string `yaml:"ciphertext"`
+}
+
+// EncryptedConfigStorage wraps a LocalConfigStorage and transparently seals whichever HanzoAgentsConfig fields are tagged
+// `secret:"true"` (see sealedFields) with AES-256-GCM before they reach
+// disk. The per-file data key is wrapped by a KEK fetched from keystore
+// under keyID, so compromising a single config file's envelope doesn't
+// expose the data key directly.
+//
+// If keystore is nil (e.g. the keystore service failed to initialize),
+// EncryptedConfigStorage falls back to plaintext so existing installs keep
+// working, logging a warning on every save.
+type EncryptedConfigStorage struct {
+	inner      *LocalConfigStorage
+	keystore   interfaces.KeyWrapper
+	keyID      string
+	sealedKeys []string
+}
+
+// NewEncryptedConfigStorage wraps fs's LocalConfigStorage with envelope
+// encryption keyed by keyID against keystore.
+func NewEncryptedConfigStorage(fs interfaces.FileSystemAdapter, keystore interfaces.KeyWrapper, keyID string) interfaces.ConfigStorage {
+	return &EncryptedConfigStorage{
+		inner:      &LocalConfigStorage{fs: fs},
+		keystore:   keystore,
+		keyID:      keyID,
+		sealedKeys: sealedFields(),
+	}
+}
+
+func (s *EncryptedConfigStorage) LoadHanzoAgentsConfig(path string) (*domain.HanzoAgentsConfig, error) {
+	if !s.inner.fs.Exists(path) {
+		return s.inner.LoadHanzoAgentsConfig(path)
+	}
+
+	data, err := s.inner.fs.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	if len(root.Content) == 0 {
+		return s.inner.LoadHanzoAgentsConfig(path)
+	}
+	doc := root.Content[0]
+
+	for _, key := range s.sealedKeys {
+		valNode := mappingValue(doc, key)
+		if valNode == nil || !isSealedNode(valNode) {
+			continue
+		}
+
+		if s.keystore == nil {
+			return nil, fmt.Errorf("storage: config %q has sealed %q section but no keystore is available to decrypt it", path, key)
+		}
+
+		var envelope sealedEnvelope
+		if err := valNode.Decode(&envelope); err != nil {
+			return nil, fmt.Errorf("storage: decoding sealed envelope for %q: %w", key, err)
+		}
+
+		plaintext, err := s.unseal(envelope, sealAAD(path, key))
+		if err != nil {
+			return nil, fmt.Errorf("storage: unsealing %q: %w", key, err)
+		}
+
+		var decrypted yaml.Node
+		if err := yaml.Unmarshal(plaintext, &decrypted); err != nil {
+			return nil, fmt.Errorf("storage: parsing decrypted %q section: %w", key, err)
+		}
+		if len(decrypted.Content) == 0 {
+			return nil, fmt.Errorf("storage: decrypted %q section for %q is empty", key, path)
+		}
+		*valNode = *decrypted.Content[0]
+	}
+
+	var config domain.HanzoAgentsConfig
+	if err := doc.Decode(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (s *EncryptedConfigStorage) SaveHanzoAgentsConfig(path string, config *domain.HanzoAgentsConfig) error {
+	if s.keystore == nil {
+		logger.Logger.Warn().Str("path", path).Msg("keystore unavailable, saving HanzoAgents config without sealing secrets")
+		return s.inner.SaveHanzoAgentsConfig(path, config)
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return err
+	}
+	if len(root.Content) == 0 {
+		return s.inner.SaveHanzoAgentsConfig(path, config)
+	}
+	doc := root.Content[0]
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return err
+	}
+
+	for _, key := range s.sealedKeys {
+		valNode := mappingValue(doc, key)
+		if valNode == nil {
+			continue
+		}
+
+		plaintext, err := yaml.Marshal(valNode)
+		if err != nil {
+			return fmt.Errorf("storage: marshaling %q section for sealing: %w", key, err)
+		}
+
+		envelope, err := s.sealWithKey(dataKey, plaintext, sealAAD(path, key))
+		if err != nil {
+			return fmt.Errorf("storage: sealing %q: %w", key, err)
+		}
+
+		if err := valNode.Encode(envelope); err != nil {
+			return fmt.Errorf("storage: encoding sealed envelope for %q: %w", key, err)
+		}
+	}
+
+	sealedData, err := yaml.Marshal(&root)
+	if err != nil {
+		return err
+	}
+
+	if err := s.inner.fs.CreateDirectory(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return s.inner.fs.WriteFile(path, sealedData)
+}
+
+// seal encrypts plaintext under a fresh random data key, then wraps that
+// data key with the configured KEK. aad (the file path and section name)
+// is bound into the GCM tag so an envelope can't be silently replayed into
+// a different file or section even though it would otherwise decrypt
+// cleanly on its own.
+func (s *EncryptedConfigStorage) seal(plaintext, aad []byte) (sealedEnvelope, error) {
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return sealedEnvelope{}, err
+	}
+	return s.sealWithKey(dataKey, plaintext, aad)
+}
+
+// sealWithKey is seal with a caller-supplied data key, so
+// SaveHanzoAgentsConfig can seal every section of one file under the same
+// per-file data key.
+func (s *EncryptedConfigStorage) sealWithKey(dataKey, plaintext, aad []byte) (sealedEnvelope, error) {
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return sealedEnvelope{}, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return sealedEnvelope{}, err
+	}
+
+	ciphertext := gcm.Seal(nil, nonce, plaintext, aad)
+
+	wrappedKey, err := s.keystore.WrapDataKey(s.keyID, dataKey)
+	if err != nil {
+		return sealedEnvelope{}, err
+	}
+
+	return sealedEnvelope{
+		Sealed:     true,
+		KeyID:      s.keyID,
+		WrappedKey: base64.StdEncoding.EncodeToString(wrappedKey),
+		Nonce:      base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}, nil
+}
+
+// unseal reverses seal: unwrap the data key, then AES-256-GCM decrypt with
+// the same aad used at seal time. A tampered nonce, ciphertext, wrapped
+// key, or an envelope moved to a different file/section is caught here,
+// since GCM authentication fails.
+func (s *EncryptedConfigStorage) unseal(envelope sealedEnvelope, aad []byte) ([]byte, error) {
+	wrappedKey, err := base64.StdEncoding.DecodeString(envelope.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("storage: decoding wrapped key: %w", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(envelope.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("storage: decoding nonce: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(envelope.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("storage: decoding ciphertext: %w", err)
+	}
+
+	dataKey, err := s.keystore.UnwrapDataKey(envelope.KeyID, wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Open(nil, nonce, ciphertext, aad)
+}
+
+// sealAAD binds an envelope to the config file it lives in and the section
+// name within that file, so copying a valid envelope elsewhere fails
+// authentication instead of silently decrypting.
+func sealAAD(path, section string) []byte {
+	return []byte(path + "\x00" + section)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// mappingValue returns the value node for key in a YAML mapping node, or
+// nil if key isn't present or doc isn't a mapping.
+func mappingValue(doc *yaml.Node, key string) *yaml.Node {
+	if doc == nil || doc.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value == key {
+			return doc.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// isSealedNode reports whether node is a mapping carrying the sealedMarker
+// field set to true, i.e. it's an envelope rather than plaintext content.
+func isSealedNode(node *yaml.Node) bool {
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == sealedMarker {
+			return strings.EqualFold(node.Content[i+1].Value, "true")
+		}
+	}
+	return false
+}
+
+// RotateConfigKeys re-seals every config file under dir from its current
+// KEK to newKeyID, backing the "hanzo-agents config rotate-keys" command.
+// Each file is decrypted with whatever KEK its own envelope names (the
+// keystore is expected to still have access to retired KEKs) and re-saved
+// under newKeyID, so files can be mid-rotation at different key IDs
+// without issue.
+func RotateConfigKeys(fs interfaces.FileSystemAdapter, dir string, keystore interfaces.KeyWrapper, newKeyID string) error {
+	entries, err := fs.ListDirectory(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if filepath.Ext(entry) != ".yaml" && filepath.Ext(entry) != ".yml" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry)
+
+		reader := NewEncryptedConfigStorage(fs, keystore, "").(*EncryptedConfigStorage)
+		config, err := reader.LoadHanzoAgentsConfig(path)
+		if err != nil {
+			return fmt.Errorf("storage: rotating keys for %q: %w", path, err)
+		}
+
+		writer := NewEncryptedConfigStorage(fs, keystore, newKeyID)
+		if err := writer.SaveHanzoAgentsConfig(path, config); err != nil {
+			return fmt.Errorf("storage: re-sealing %q under new key: %w", path, err)
+		}
+	}
+
+	return nil
+}
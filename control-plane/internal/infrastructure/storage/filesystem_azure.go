@@ -0,0 +1,91 @@
+// hanzo-agents/internal/infrastructure/storage/filesystem_azure.go
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+
+	"github.com/hanzoai/agents/control-plane/internal/config"
+	"github.com/hanzoai/agents/control-plane/internal/core/interfaces"
+)
+
+// azureObjectStore adapts an Azure Blob container client to objectStoreClient.
+type azureObjectStore struct {
+	client *container.Client
+}
+
+// NewAzureBlobFileSystemAdapter returns a FileSystemAdapter backed by the
+// Azure Blob container identified by containerName, selected via the
+// "azblob://<container>/..." URI scheme.
+func NewAzureBlobFileSystemAdapter(containerName string, cfg config.AzureFilesystemConfig) (interfaces.FileSystemAdapter, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net", cfg.AccountName)
+	}
+
+	cred, err := azblob.NewSharedKeyCredential(cfg.AccountName, cfg.AccountKey)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := container.NewClientWithSharedKeyCredential(endpoint+"/"+containerName, cred, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return newObjectStoreAdapter(&azureObjectStore{client: client}), nil
+}
+
+func (a *azureObjectStore) Get(key string) ([]byte, error) {
+	ctx := context.Background()
+	resp, err := a.client.NewBlobClient(key).DownloadStream(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+func (a *azureObjectStore) Put(key string, data []byte) error {
+	ctx := context.Background()
+	_, err := a.client.NewBlockBlobClient(key).UploadBuffer(ctx, data, nil)
+	return err
+}
+
+func (a *azureObjectStore) Has(key string) (bool, error) {
+	ctx := context.Background()
+	_, err := a.client.NewBlobClient(key).GetProperties(ctx, nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *azureObjectStore) List(prefix string) ([]string, error) {
+	ctx := context.Background()
+	pager := a.client.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{Prefix: &prefix})
+
+	var keys []string
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, blob := range page.Segment.BlobItems {
+			if blob.Name != nil {
+				keys = append(keys, *blob.Name)
+			}
+		}
+	}
+
+	return keys, nil
+}
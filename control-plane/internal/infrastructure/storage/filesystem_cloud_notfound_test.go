@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/require"
+)
+
+// These exercise the not-found classification s3ObjectStore.Has and
+// azureObjectStore.Has rely on directly, since the real methods need a live
+// S3/Azure client (see the adaptersUnderTest comment in
+// filesystem_contract_test.go). They check that a HeadObject/GetProperties
+// failure other than "not found" (auth,
+// network, throttling) must come back as an error, not get reported as
+// "key doesn't exist" the way gcsObjectStore.Has already distinguishes via
+// storage.ErrObjectNotExist.
+
+func TestIsS3NotFound(t *testing.T) {
+	require.True(t, isS3NotFound(&s3types.NotFound{}))
+	require.False(t, isS3NotFound(errors.New("access denied")))
+	require.False(t, isS3NotFound(nil))
+}
+
+func TestAzureHasCodeBlobNotFound(t *testing.T) {
+	notFound := &azcore.ResponseError{ErrorCode: string(bloberror.BlobNotFound)}
+	require.True(t, bloberror.HasCode(notFound, bloberror.BlobNotFound))
+
+	other := &azcore.ResponseError{ErrorCode: string(bloberror.AuthenticationFailed)}
+	require.False(t, bloberror.HasCode(other, bloberror.BlobNotFound))
+
+	require.False(t, bloberror.HasCode(errors.New("network timeout"), bloberror.BlobNotFound))
+}
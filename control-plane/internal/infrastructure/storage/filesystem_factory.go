@@ -0,0 +1,49 @@
+// hanzo-agents/internal/infrastructure/storage/filesystem_factory.go
+package storage
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/hanzoai/agents/control-plane/internal/config"
+	"github.com/hanzoai/agents/control-plane/internal/core/interfaces"
+)
+
+// Supported FileSystemAdapter URI schemes.
+const (
+	SchemeFile   = "file"
+	SchemeS3     = "s3"
+	SchemeGCS    = "gs"
+	SchemeAzure  = "azblob"
+	SchemeMemory = "memory"
+)
+
+// NewFileSystemAdapterForURI inspects uri's scheme and returns the matching
+// interfaces.FileSystemAdapter implementation, configured from cfg. A bare
+// path or a "file://" URI returns the existing local adapter so callers that
+// don't configure remote storage see no behavior change.
+func NewFileSystemAdapterForURI(uri string, cfg config.FilesystemConfig) (interfaces.FileSystemAdapter, error) {
+	if uri == "" {
+		return NewFileSystemAdapter(), nil
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid filesystem URI %q: %w", uri, err)
+	}
+
+	switch parsed.Scheme {
+	case "", SchemeFile:
+		return NewFileSystemAdapter(), nil
+	case SchemeS3:
+		return NewS3FileSystemAdapter(parsed.Host, cfg.S3)
+	case SchemeGCS:
+		return NewGCSFileSystemAdapter(parsed.Host, cfg.GCS)
+	case SchemeAzure:
+		return NewAzureBlobFileSystemAdapter(parsed.Host, cfg.Azure)
+	case SchemeMemory:
+		return NewMemoryFileSystemAdapter(), nil
+	default:
+		return nil, fmt.Errorf("storage: unsupported filesystem URI scheme %q", parsed.Scheme)
+	}
+}
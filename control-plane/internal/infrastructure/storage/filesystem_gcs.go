@@ -0,0 +1,91 @@
+// hanzo-agents/internal/infrastructure/storage/filesystem_gcs.go
+package storage
+
+import (
+	"context"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+
+	hanzoagentsConfig "github.com/hanzoai/agents/control-plane/internal/config"
+	"github.com/hanzoai/agents/control-plane/internal/core/interfaces"
+)
+
+// gcsObjectStore adapts a Google Cloud Storage client + bucket to objectStoreClient.
+type gcsObjectStore struct {
+	client *storage.Client
+	bucket string
+}
+
+// NewGCSFileSystemAdapter returns a FileSystemAdapter backed by the GCS
+// bucket identified by bucket, selected via the "gs://<bucket>/..." URI scheme.
+func NewGCSFileSystemAdapter(bucket string, cfg hanzoagentsConfig.GCSFilesystemConfig) (interfaces.FileSystemAdapter, error) {
+	ctx := context.Background()
+
+	var opts []option.ClientOption
+	if cfg.CredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsFile))
+	}
+
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newObjectStoreAdapter(&gcsObjectStore{client: client, bucket: bucket}), nil
+}
+
+func (g *gcsObjectStore) Get(key string) ([]byte, error) {
+	ctx := context.Background()
+	r, err := g.client.Bucket(g.bucket).Object(key).NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+func (g *gcsObjectStore) Put(key string, data []byte) error {
+	ctx := context.Background()
+	w := g.client.Bucket(g.bucket).Object(key).NewWriter(ctx)
+
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (g *gcsObjectStore) Has(key string) (bool, error) {
+	ctx := context.Background()
+	_, err := g.client.Bucket(g.bucket).Object(key).Attrs(ctx)
+	if err == storage.ErrObjectNotExist {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (g *gcsObjectStore) List(prefix string) ([]string, error) {
+	ctx := context.Background()
+	it := g.client.Bucket(g.bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+
+	var keys []string
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, attrs.Name)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,103 @@
+// hanzo-agents/internal/infrastructure/storage/filesystem_local.go
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hanzoai/agents/control-plane/internal/core/interfaces"
+	"github.com/hanzoai/agents/control-plane/internal/utils"
+)
+
+// localFileSystemAdapter implements interfaces.FileSystemAdapter directly
+// against the local disk via the os package. It's the default returned for
+// a bare path or "file://" URI, so installs that don't configure a remote
+// filesystem backend see no behavior change.
+type localFileSystemAdapter struct{}
+
+// NewFileSystemAdapter returns a FileSystemAdapter backed by the local
+// filesystem.
+func NewFileSystemAdapter() interfaces.FileSystemAdapter {
+	return &localFileSystemAdapter{}
+}
+
+func (a *localFileSystemAdapter) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (a *localFileSystemAdapter) WriteFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func (a *localFileSystemAdapter) Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (a *localFileSystemAdapter) CreateDirectory(path string) error {
+	return os.MkdirAll(path, 0755)
+}
+
+func (a *localFileSystemAdapter) ListDirectory(path string) ([]string, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// quotaEnforcingFileSystemAdapter wraps any interfaces.FileSystemAdapter
+// and refuses WriteFile once a write would bring category to or over its
+// hard quota as monitor last measured it, the same way EncryptedConfigStorage wraps a plain ConfigStorage to
+// add a cross-cutting concern without changing the inner adapter's
+// behavior.
+type quotaEnforcingFileSystemAdapter struct {
+	inner    interfaces.FileSystemAdapter
+	monitor  *utils.DiskUsageMonitor
+	category string
+}
+
+// NewQuotaEnforcingFileSystemAdapter wraps inner so WriteFile returns
+// *utils.ErrQuotaExceeded instead of writing when category's usage from
+// monitor's last poll plus len(data) is at or over its configured hard
+// quota. monitor may be nil (e.g.
+// disk usage monitoring failed to start), in which case writes are never
+// refused.
+func NewQuotaEnforcingFileSystemAdapter(inner interfaces.FileSystemAdapter, monitor *utils.DiskUsageMonitor, category string) interfaces.FileSystemAdapter {
+	return &quotaEnforcingFileSystemAdapter{inner: inner, monitor: monitor, category: category}
+}
+
+func (a *quotaEnforcingFileSystemAdapter) ReadFile(path string) ([]byte, error) {
+	return a.inner.ReadFile(path)
+}
+
+func (a *quotaEnforcingFileSystemAdapter) WriteFile(path string, data []byte) error {
+	if a.monitor != nil {
+		if err := a.monitor.CheckQuota(a.category, int64(len(data))); err != nil {
+			return err
+		}
+	}
+	return a.inner.WriteFile(path, data)
+}
+
+func (a *quotaEnforcingFileSystemAdapter) Exists(path string) bool {
+	return a.inner.Exists(path)
+}
+
+func (a *quotaEnforcingFileSystemAdapter) CreateDirectory(path string) error {
+	return a.inner.CreateDirectory(path)
+}
+
+func (a *quotaEnforcingFileSystemAdapter) ListDirectory(path string) ([]string, error) {
+	return a.inner.ListDirectory(path)
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hanzoai/agents/control-plane/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalFileSystemAdapter_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	fs := NewFileSystemAdapter()
+
+	path := filepath.Join(dir, "nested", "config.yaml")
+	require.False(t, fs.Exists(path))
+
+	require.NoError(t, fs.WriteFile(path, []byte("hello")))
+	require.True(t, fs.Exists(path))
+
+	data, err := fs.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "hello", string(data))
+
+	entries, err := fs.ListDirectory(filepath.Join(dir, "nested"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"config.yaml"}, entries)
+}
+
+func TestQuotaEnforcingFileSystemAdapter_RefusesWriteOverHardQuota(t *testing.T) {
+	dirs := &utils.DataDirectories{HanzoAgentsHome: t.TempDir(), PayloadsDir: t.TempDir()}
+	quotas := map[string]utils.CategoryQuota{utils.CategoryPayloads: {HardBytes: 1}}
+	mon := utils.NewDiskUsageMonitor(dirs, quotas, nil, nil)
+	_, err := mon.Poll()
+	require.NoError(t, err)
+
+	fs := NewQuotaEnforcingFileSystemAdapter(NewMemoryFileSystemAdapter(), mon, utils.CategoryPayloads)
+
+	writeErr := fs.WriteFile("payload.bin", make([]byte, 1))
+	require.Error(t, writeErr)
+
+	var quotaErr *utils.ErrQuotaExceeded
+	require.ErrorAs(t, writeErr, &quotaErr)
+	require.Equal(t, utils.CategoryPayloads, quotaErr.Category)
+}
+
+func TestQuotaEnforcingFileSystemAdapter_AllowsWriteUnderQuota(t *testing.T) {
+	dirs := &utils.DataDirectories{HanzoAgentsHome: t.TempDir(), PayloadsDir: t.TempDir()}
+	quotas := map[string]utils.CategoryQuota{utils.CategoryPayloads: {HardBytes: 4096}}
+	mon := utils.NewDiskUsageMonitor(dirs, quotas, nil, nil)
+	_, err := mon.Poll()
+	require.NoError(t, err)
+
+	fs := NewQuotaEnforcingFileSystemAdapter(NewMemoryFileSystemAdapter(), mon, utils.CategoryPayloads)
+	require.NoError(t, fs.WriteFile("payload.bin", make([]byte, 100)))
+}
+
+func TestQuotaEnforcingFileSystemAdapter_NilMonitorNeverRefuses(t *testing.T) {
+	fs := NewQuotaEnforcingFileSystemAdapter(NewMemoryFileSystemAdapter(), nil, utils.CategoryPayloads)
+	require.NoError(t, fs.WriteFile("payload.bin", make([]byte, 1<<20)))
+}
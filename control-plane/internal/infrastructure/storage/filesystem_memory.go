@@ -0,0 +1,69 @@
+// hanzo-agents/internal/infrastructure/storage/filesystem_memory.go
+package storage
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hanzoai/agents/control-plane/internal/core/interfaces"
+)
+
+// memoryObjectStore is an in-process objectStoreClient backed by a map. It
+// exists so tests (and the memory:// URI scheme) can exercise the same
+// interfaces.FileSystemAdapter contract as the real cloud backends without
+// any network or credentials.
+type memoryObjectStore struct {
+	mu      sync.RWMutex
+	objects map[string][]byte
+}
+
+// NewMemoryFileSystemAdapter returns a FileSystemAdapter backed by an
+// in-memory map, selected via the "memory://" URI scheme.
+func NewMemoryFileSystemAdapter() interfaces.FileSystemAdapter {
+	return newObjectStoreAdapter(&memoryObjectStore{objects: make(map[string][]byte)})
+}
+
+func (m *memoryObjectStore) Get(key string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("storage: object %q not found", key)
+	}
+
+	out := make([]byte, len(data))
+	copy(out, data)
+	return out, nil
+}
+
+func (m *memoryObjectStore) Put(key string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := make([]byte, len(data))
+	copy(stored, data)
+	m.objects[key] = stored
+	return nil
+}
+
+func (m *memoryObjectStore) Has(key string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	_, ok := m.objects[key]
+	return ok, nil
+}
+
+func (m *memoryObjectStore) List(prefix string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var keys []string
+	for key := range m.objects {
+		if len(prefix) == 0 || (len(key) >= len(prefix) && key[:len(prefix)] == prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
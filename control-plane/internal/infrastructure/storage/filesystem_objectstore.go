@@ -0,0 +1,115 @@
+// hanzo-agents/internal/infrastructure/storage/filesystem_objectstore.go
+package storage
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hanzoai/agents/control-plane/internal/logger"
+)
+
+// objectStoreClient is the minimal operation set an object-storage backend
+// (S3, GCS, Azure Blob, ...) must provide for objectStoreAdapter to satisfy
+// interfaces.FileSystemAdapter on top of it. Keys are always "/"-joined,
+// slash-free of a leading separator.
+type objectStoreClient interface {
+	Get(key string) ([]byte, error)
+	Put(key string, data []byte) error
+	Has(key string) (bool, error)
+	// List returns every key with the given prefix, including keys nested
+	// under "subdirectories" of that prefix.
+	List(prefix string) ([]string, error)
+}
+
+// objectStoreAdapter adapts any objectStoreClient to interfaces.FileSystemAdapter
+// by translating HanzoAgents' directory-oriented calls into key-prefix
+// operations: CreateDirectory is a no-op (object stores have no directories
+// to create), and ListDirectory derives immediate children from the set of
+// keys sharing the requested prefix.
+type objectStoreAdapter struct {
+	client objectStoreClient
+}
+
+func newObjectStoreAdapter(client objectStoreClient) *objectStoreAdapter {
+	return &objectStoreAdapter{client: client}
+}
+
+func (a *objectStoreAdapter) ReadFile(path string) ([]byte, error) {
+	return a.client.Get(normalizeKey(path))
+}
+
+func (a *objectStoreAdapter) WriteFile(path string, data []byte) error {
+	return a.client.Put(normalizeKey(path), data)
+}
+
+func (a *objectStoreAdapter) Exists(path string) bool {
+	key := normalizeKey(path)
+
+	ok, err := a.client.Has(key)
+	if err == nil && ok {
+		return true
+	}
+	if err != nil {
+		// interfaces.FileSystemAdapter.Exists is bool-only (matching the
+		// os.Stat-err==nil pattern localFileSystemAdapter.Exists already
+		// uses), so a real Has() failure -- auth, network, anything other
+		// than "no such key" -- can't be returned to the caller here. Log
+		// it rather than let it look identical to a clean not-found, since
+		// Has() now distinguishes the two (see isS3NotFound,
+		// bloberror.HasCode).
+		logger.Logger.Warn().Err(err).Str("key", key).Msg("object store Has check failed, falling back to prefix listing before reporting not-found")
+	}
+
+	// "Directories" have no object of their own; treat any key under the
+	// prefix as evidence the directory exists.
+	keys, err := a.client.List(key + "/")
+	if err != nil {
+		logger.Logger.Warn().Err(err).Str("key", key).Msg("object store List check failed, reporting not-found")
+	}
+	return err == nil && len(keys) > 0
+}
+
+// CreateDirectory is a no-op: object stores have no directory entities, and
+// a key written under this prefix later is enough to make it "exist".
+func (a *objectStoreAdapter) CreateDirectory(path string) error {
+	return nil
+}
+
+func (a *objectStoreAdapter) ListDirectory(path string) ([]string, error) {
+	prefix := normalizeKey(path)
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	keys, err := a.client.List(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]struct{})
+	var entries []string
+	for _, key := range keys {
+		rest := strings.TrimPrefix(key, prefix)
+		if rest == "" {
+			continue
+		}
+		child := rest
+		if idx := strings.Index(rest, "/"); idx >= 0 {
+			child = rest[:idx]
+		}
+		if _, ok := seen[child]; ok {
+			continue
+		}
+		seen[child] = struct{}{}
+		entries = append(entries, child)
+	}
+
+	sort.Strings(entries)
+	return entries, nil
+}
+
+// normalizeKey strips leading slashes so object keys never start with "/",
+// matching the convention every supported object store uses internally.
+func normalizeKey(path string) string {
+	return strings.TrimPrefix(path, "/")
+}
@@ -0,0 +1,121 @@
+// hanzo-agents/internal/infrastructure/storage/filesystem_s3.go
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/hanzoai/agents/control-plane/internal/config"
+	"github.com/hanzoai/agents/control-plane/internal/core/interfaces"
+)
+
+// s3ObjectStore adapts an AWS S3 client + bucket to objectStoreClient.
+type s3ObjectStore struct {
+	client *s3.Client
+	bucket string
+}
+
+// NewS3FileSystemAdapter returns a FileSystemAdapter backed by the S3 bucket
+// identified by bucket, selected via the "s3://<bucket>/..." URI scheme.
+func NewS3FileSystemAdapter(bucket string, cfg config.S3FilesystemConfig) (interfaces.FileSystemAdapter, error) {
+	ctx := context.Background()
+
+	opts := []func(*awsconfig.LoadOptions) error{}
+	if cfg.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.Region))
+	}
+	if cfg.AccessKeyID != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		))
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+		o.UsePathStyle = cfg.PathStyle
+	})
+
+	return newObjectStoreAdapter(&s3ObjectStore{client: client, bucket: bucket}), nil
+}
+
+func (s *s3ObjectStore) Get(key string) ([]byte, error) {
+	out, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+
+	return io.ReadAll(out.Body)
+}
+
+func (s *s3ObjectStore) Put(key string, data []byte) error {
+	_, err := s.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(data),
+	})
+	return err
+}
+
+func (s *s3ObjectStore) Has(key string) (bool, error) {
+	_, err := s.client.HeadObject(context.Background(), &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if isS3NotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isS3NotFound reports whether err is S3's "no such key" response, as
+// opposed to an auth failure, network error or other HeadObject failure
+// that callers need to see rather than have silently treated as "the key
+// doesn't exist" (mirrors gcsObjectStore.Has's storage.ErrObjectNotExist
+// check).
+func isS3NotFound(err error) bool {
+	var notFound *s3types.NotFound
+	return errors.As(err, &notFound)
+}
+
+func (s *s3ObjectStore) List(prefix string) ([]string, error) {
+	var keys []string
+
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+	}
+
+	return keys, nil
+}
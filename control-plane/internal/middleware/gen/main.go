@@ -0,0 +1,309 @@
+// Command servicedecorator generates a panic-recovering, instrumented
+// decorator for a service interface, so every exported method on that
+// interface is automatically wrapped with middleware.Recoverer.Call without
+// anyone having to remember to do it by hand when a method is added.
+//
+// Usage (typically invoked via a //go:generate directive in the package
+// that defines the interface):
+//
+//	//go:generate go run github.com/hanzoai/agents/control-plane/internal/middleware/gen -type PackageService -out packageservice_decorator_gen.go
+//
+// The generated file defines a `<Type>Decorator` struct holding the wrapped
+// interface in an unexported field and a constructor `NewInstrumented<Type>` that wraps every
+// method in a middleware.Recoverer.Call, using the method name as-is for
+// logging/metrics labels. Every decorated method must take a
+// context.Context as its first parameter; the last result, if any, must be
+// `error` for panics to surface through the method's own return value
+// instead of only being logged.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+var (
+	typeName = flag.String("type", "", "name of the interface to decorate (required)")
+	outFile  = flag.String("out", "", "output file path (defaults to <type>_decorator_gen.go)")
+	srcDir   = flag.String("dir", ".", "directory to scan for the interface declaration")
+)
+
+type result struct {
+	Name string
+	Type string
+}
+
+type methodSpec struct {
+	Name string
+	// Params is the method's parameter list rendered as Go source,
+	// excluding the leading context.Context (handled separately by the
+	// template since every decorated method must accept one).
+	Params string
+	// Args is the corresponding argument list for calling through to the
+	// wrapped implementation.
+	Args    string
+	Results []result
+	// ReturnsError is true when the method's last result is exactly
+	// `error`; only then can a recovered panic surface through the
+	// method's own return value rather than just being logged.
+	ReturnsError bool
+}
+
+// AssignTargets is "r0, r1" for the non-error results, used on the left of
+// the inner call's assignment.
+func (m methodSpec) AssignTargets() string {
+	var names []string
+	for _, r := range m.Results {
+		if r.Type == "error" {
+			continue
+		}
+		names = append(names, r.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// NamedResults renders the method's named return list, e.g. "r0 int, err error".
+func (m methodSpec) NamedResults() string {
+	var parts []string
+	for _, r := range m.Results {
+		parts = append(parts, r.Name+" "+r.Type)
+	}
+	return strings.Join(parts, ", ")
+}
+
+type fileData struct {
+	Package string
+	Type    string
+	Methods []methodSpec
+}
+
+func main() {
+	flag.Parse()
+	if *typeName == "" {
+		log.Fatal("servicedecorator: -type is required")
+	}
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, *srcDir, nil, parser.AllErrors)
+	if err != nil {
+		log.Fatalf("servicedecorator: parsing %s: %v", *srcDir, err)
+	}
+
+	var (
+		pkgName string
+		iface   *ast.InterfaceType
+	)
+
+	for name, pkg := range pkgs {
+		if strings.HasSuffix(name, "_test") {
+			continue
+		}
+		for _, file := range pkg.Files {
+			ast.Inspect(file, func(n ast.Node) bool {
+				ts, ok := n.(*ast.TypeSpec)
+				if !ok || ts.Name.Name != *typeName {
+					return true
+				}
+				it, ok := ts.Type.(*ast.InterfaceType)
+				if !ok {
+					return true
+				}
+				iface = it
+				pkgName = pkg.Name
+				return false
+			})
+		}
+	}
+
+	if iface == nil {
+		log.Fatalf("servicedecorator: interface %q not found under %s", *typeName, *srcDir)
+	}
+
+	methods := collectMethods(iface)
+	data := fileData{Package: pkgName, Type: *typeName, Methods: methods}
+
+	out := *outFile
+	if out == "" {
+		out = filepath.Join(*srcDir, strings.ToLower(*typeName)+"_decorator_gen.go")
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		log.Fatalf("servicedecorator: creating %s: %v", out, err)
+	}
+	defer f.Close()
+
+	if err := decoratorTemplate.Execute(f, data); err != nil {
+		log.Fatalf("servicedecorator: rendering %s: %v", out, err)
+	}
+}
+
+// collectMethods extracts each method's formatted parameter/result lists
+// from the interface AST, skipping the leading context.Context parameter
+// that every decorated method is expected to declare. Parameter and result
+// names are synthesized (p0, p1, ... / r0, r1, ...) since interface
+// declarations don't require named parameters or results.
+func collectMethods(iface *ast.InterfaceType) []methodSpec {
+	var methods []methodSpec
+
+	for _, field := range iface.Methods.List {
+		ft, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			// Embedded interfaces aren't supported by this generator; list
+			// the service's methods directly.
+			continue
+		}
+
+		var params, args []string
+		paramIdx := 0
+		skippedContext := false
+
+		if ft.Params != nil {
+			for _, p := range ft.Params.List {
+				typ := exprString(p.Type)
+				count := len(p.Names)
+				if count == 0 {
+					count = 1
+				}
+				for i := 0; i < count; i++ {
+					if !skippedContext && typ == "context.Context" {
+						skippedContext = true
+						continue
+					}
+					name := fmt.Sprintf("p%d", paramIdx)
+					params = append(params, name+" "+typ)
+					args = append(args, name)
+					paramIdx++
+				}
+			}
+		}
+
+		var results []result
+		returnsError := false
+		if ft.Results != nil {
+			idx := 0
+			for _, r := range ft.Results.List {
+				typ := exprString(r.Type)
+				count := len(r.Names)
+				if count == 0 {
+					count = 1
+				}
+				for i := 0; i < count; i++ {
+					results = append(results, result{Name: fmt.Sprintf("r%d", idx), Type: typ})
+					idx++
+				}
+			}
+			if n := len(results); n > 0 {
+				results[n-1].Name = "err"
+				returnsError = results[n-1].Type == "error"
+			}
+		}
+
+		methods = append(methods, methodSpec{
+			Name:         field.Names[0].Name,
+			Params:       strings.Join(params, ", "),
+			Args:         strings.Join(args, ", "),
+			Results:      results,
+			ReturnsError: returnsError,
+		})
+	}
+
+	return methods
+}
+
+func exprString(expr ast.Expr) string {
+	var sb strings.Builder
+	if err := printExpr(&sb, expr); err != nil {
+		return fmt.Sprintf("%T", expr)
+	}
+	return sb.String()
+}
+
+// printExpr renders a (possibly qualified/pointer/slice) type expression
+// back to source form without needing a full types.Info.
+func printExpr(sb *strings.Builder, expr ast.Expr) error {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		sb.WriteString(e.Name)
+	case *ast.SelectorExpr:
+		if err := printExpr(sb, e.X); err != nil {
+			return err
+		}
+		sb.WriteString(".")
+		sb.WriteString(e.Sel.Name)
+	case *ast.StarExpr:
+		sb.WriteString("*")
+		return printExpr(sb, e.X)
+	case *ast.ArrayType:
+		sb.WriteString("[]")
+		return printExpr(sb, e.Elt)
+	case *ast.Ellipsis:
+		sb.WriteString("...")
+		return printExpr(sb, e.Elt)
+	case *ast.MapType:
+		sb.WriteString("map[")
+		if err := printExpr(sb, e.Key); err != nil {
+			return err
+		}
+		sb.WriteString("]")
+		return printExpr(sb, e.Value)
+	case *ast.InterfaceType:
+		sb.WriteString("interface{}")
+	default:
+		return fmt.Errorf("unsupported type expression %T", expr)
+	}
+	return nil
+}
+
+var decoratorTemplate = template.Must(template.New("decorator").Parse(`// Code generated by servicedecorator; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+
+	"github.com/hanzoai/agents/control-plane/internal/middleware"
+)
+
+// {{.Type}}Decorator wraps a {{.Type}} implementation with panic recovery,
+// structured error logging and latency/error metrics for every method, via
+// middleware.Recoverer. Regenerate with 'go generate' after adding or
+// changing methods on {{.Type}}.
+type {{.Type}}Decorator struct {
+	inner     {{.Type}}
+	recoverer *middleware.Recoverer
+}
+
+// NewInstrumented{{.Type}} wraps inner so every call is recovered, logged
+// and measured under the "{{.Type}}" service name.
+func NewInstrumented{{.Type}}(inner {{.Type}}, metrics *middleware.Metrics) {{.Type}} {
+	return &{{.Type}}Decorator{
+		inner:     inner,
+		recoverer: middleware.NewRecoverer("{{.Type}}", metrics),
+	}
+}
+{{range .Methods}}
+func (d *{{$.Type}}Decorator) {{.Name}}(ctx context.Context{{if .Params}}, {{.Params}}{{end}}) ({{.NamedResults}}) {
+	{{if .ReturnsError -}}
+	err = d.recoverer.Call(ctx, "{{.Name}}", func() error {
+		{{if .AssignTargets}}{{.AssignTargets}}, err = d.inner.{{.Name}}(ctx{{if .Args}}, {{.Args}}{{end}})
+		return err{{else}}return d.inner.{{.Name}}(ctx{{if .Args}}, {{.Args}}{{end}}){{end}}
+	})
+	{{- else -}}
+	_ = d.recoverer.Call(ctx, "{{.Name}}", func() error {
+		{{if .AssignTargets}}{{.AssignTargets}} = {{end}}d.inner.{{.Name}}(ctx{{if .Args}}, {{.Args}}{{end}})
+		return nil
+	})
+	{{- end}}
+	return
+}
+{{end}}
+`))
@@ -0,0 +1,59 @@
+// hanzo-agents/internal/middleware/metrics.go
+package middleware
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics records per-method latency and error counts for services wrapped
+// by a Recoverer. It is optional: services run fine without a Prometheus
+// registry, they just don't get metrics.
+type Metrics struct {
+	latency *prometheus.HistogramVec
+	errors  *prometheus.CounterVec
+}
+
+// NewMetrics registers the service-method latency histogram and error
+// counter on reg and returns a Metrics that reports to them. reg must not
+// be nil; pass a nil *Metrics (not a Metrics backed by a throwaway
+// registry) to disable metrics instead.
+func NewMetrics(reg prometheus.Registerer) (*Metrics, error) {
+	latency := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "hanzo_agents",
+		Subsystem: "service",
+		Name:      "method_duration_seconds",
+		Help:      "Latency of service container method calls, wrapped by the recovery middleware.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method"})
+
+	errors := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "hanzo_agents",
+		Subsystem: "service",
+		Name:      "method_errors_total",
+		Help:      "Count of service container method calls that returned an error, including recovered panics.",
+	}, []string{"service", "method"})
+
+	if err := reg.Register(latency); err != nil {
+		return nil, err
+	}
+	if err := reg.Register(errors); err != nil {
+		return nil, err
+	}
+
+	return &Metrics{latency: latency, errors: errors}, nil
+}
+
+// Observe records one call to service.method that took duration and either
+// succeeded or returned an error.
+func (m *Metrics) Observe(service, method string, duration time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+
+	m.latency.WithLabelValues(service, method).Observe(duration.Seconds())
+	if failed {
+		m.errors.WithLabelValues(service, method).Inc()
+	}
+}
@@ -0,0 +1,119 @@
+// hanzo-agents/internal/middleware/recovery.go
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/hanzoai/agents/control-plane/internal/logger"
+)
+
+// correlationIDKey is the context key under which a request-scoped
+// correlation ID is stored. Callers set it with WithCorrelationID and
+// Call reads it back to tag both the log line and the returned error.
+type correlationIDKey struct{}
+
+// WithCorrelationID attaches id to ctx so that any Recoverer.Call made
+// with the resulting context logs and reports errors tagged with it.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the correlation ID attached to ctx, or "" if none
+// was set.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// PanicError wraps a recovered panic value as a normal error, preserving the
+// originating service/method and a captured stack trace so it can be logged
+// or inspected without crashing the caller.
+type PanicError struct {
+	Service       string
+	Method        string
+	CorrelationID string
+	Recovered     interface{}
+	Stack         []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s.%s: recovered panic: %v", e.Service, e.Method, e.Recovered)
+}
+
+// Unwrap lets errors.As/Is see through to the recovered value when it is
+// itself an error (e.g. a panic(err)).
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Recovered.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Recoverer wraps service methods with panic recovery, structured logging
+// and latency/error instrumentation. A zero-value Recoverer is safe to use
+// (metrics are simply not recorded without a Metrics set).
+type Recoverer struct {
+	// Service is the name attached to every log line and metric emitted by
+	// this Recoverer, e.g. "PackageService".
+	Service string
+	// Metrics, if non-nil, receives per-method latency and error counts.
+	// It is optional so callers without a Prometheus registry wired up
+	// still get recovery and logging.
+	Metrics *Metrics
+}
+
+// NewRecoverer builds a Recoverer for service, reporting to metrics if given.
+func NewRecoverer(service string, metrics *Metrics) *Recoverer {
+	return &Recoverer{Service: service, Metrics: metrics}
+}
+
+// Call invokes fn, recovering any panic into a *PanicError, logging the
+// outcome with the correlation ID from ctx, and recording latency/error
+// counts for method. It returns fn's error unchanged on normal completion.
+func (r *Recoverer) Call(ctx context.Context, method string, fn func() error) (err error) {
+	start := time.Now()
+	correlationID := CorrelationID(ctx)
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			stack := make([]byte, 64<<10)
+			stack = stack[:runtime.Stack(stack, false)]
+
+			err = &PanicError{
+				Service:       r.Service,
+				Method:        method,
+				CorrelationID: correlationID,
+				Recovered:     rec,
+				Stack:         stack,
+			}
+
+			logger.Logger.Error().
+				Str("service", r.Service).
+				Str("method", method).
+				Str("correlation_id", correlationID).
+				Interface("panic", rec).
+				Bytes("stack", stack).
+				Msg("recovered panic in service method")
+		}
+
+		duration := time.Since(start)
+		if r.Metrics != nil {
+			r.Metrics.Observe(r.Service, method, duration, err != nil)
+		}
+
+		if err != nil {
+			logger.Logger.Error().
+				Str("service", r.Service).
+				Str("method", method).
+				Str("correlation_id", correlationID).
+				Dur("duration", duration).
+				Err(err).
+				Msg("service method returned an error")
+		}
+	}()
+
+	return fn()
+}
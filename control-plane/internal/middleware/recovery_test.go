@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeService and fakeServiceDecorator stand in for a real container
+// service and the decorator servicedecorator would generate for it, so
+// this test exercises the exact shape callers see in production: a normal
+// interface whose panicking implementation never reaches the caller as a
+// panic.
+type fakeService interface {
+	DoPanic(ctx context.Context) (string, error)
+	DoError(ctx context.Context) (string, error)
+	DoOK(ctx context.Context) (string, error)
+}
+
+type panickyFakeService struct{}
+
+func (panickyFakeService) DoPanic(ctx context.Context) (string, error) {
+	panic("boom")
+}
+
+func (panickyFakeService) DoError(ctx context.Context) (string, error) {
+	return "", errors.New("explicit failure")
+}
+
+func (panickyFakeService) DoOK(ctx context.Context) (string, error) {
+	return "ok", nil
+}
+
+type fakeServiceDecorator struct {
+	inner     fakeService
+	recoverer *Recoverer
+}
+
+func newInstrumentedFakeService(inner fakeService, metrics *Metrics) fakeService {
+	return &fakeServiceDecorator{inner: inner, recoverer: NewRecoverer("fakeService", metrics)}
+}
+
+func (d *fakeServiceDecorator) DoPanic(ctx context.Context) (r0 string, err error) {
+	err = d.recoverer.Call(ctx, "DoPanic", func() error {
+		r0, err = d.inner.DoPanic(ctx)
+		return err
+	})
+	return
+}
+
+func (d *fakeServiceDecorator) DoError(ctx context.Context) (r0 string, err error) {
+	err = d.recoverer.Call(ctx, "DoError", func() error {
+		r0, err = d.inner.DoError(ctx)
+		return err
+	})
+	return
+}
+
+func (d *fakeServiceDecorator) DoOK(ctx context.Context) (r0 string, err error) {
+	err = d.recoverer.Call(ctx, "DoOK", func() error {
+		r0, err = d.inner.DoOK(ctx)
+		return err
+	})
+	return
+}
+
+func TestRecoverer_PanicBecomesError(t *testing.T) {
+	svc := newInstrumentedFakeService(panickyFakeService{}, nil)
+
+	var result string
+	require.NotPanics(t, func() {
+		var err error
+		result, err = svc.DoPanic(context.Background())
+		require.Error(t, err)
+
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+		require.Equal(t, "fakeService", panicErr.Service)
+		require.Equal(t, "DoPanic", panicErr.Method)
+		require.Equal(t, "boom", panicErr.Recovered)
+		require.NotEmpty(t, panicErr.Stack)
+	})
+	require.Empty(t, result)
+}
+
+func TestRecoverer_PassesThroughErrorsAndSuccess(t *testing.T) {
+	svc := newInstrumentedFakeService(panickyFakeService{}, nil)
+
+	_, err := svc.DoError(context.Background())
+	require.EqualError(t, err, "explicit failure")
+
+	result, err := svc.DoOK(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "ok", result)
+}
+
+func TestRecoverer_CorrelationIDPropagatesToPanicError(t *testing.T) {
+	ctx := WithCorrelationID(context.Background(), "corr-123")
+	svc := newInstrumentedFakeService(panickyFakeService{}, nil)
+
+	_, err := svc.DoPanic(ctx)
+	var panicErr *PanicError
+	require.ErrorAs(t, err, &panicErr)
+	require.Equal(t, "corr-123", panicErr.CorrelationID)
+}
+
+func TestMetrics_RecordsLatencyAndErrors(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	metrics, err := NewMetrics(reg)
+	require.NoError(t, err)
+
+	svc := newInstrumentedFakeService(panickyFakeService{}, metrics)
+
+	_, _ = svc.DoOK(context.Background())
+	_, _ = svc.DoError(context.Background())
+	_, _ = svc.DoPanic(context.Background())
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	var errorsTotal float64
+	for _, mf := range families {
+		if mf.GetName() != "hanzo_agents_service_method_errors_total" {
+			continue
+		}
+		for _, m := range mf.Metric {
+			errorsTotal += m.GetCounter().GetValue()
+		}
+	}
+	require.Equal(t, float64(2), errorsTotal) // DoError and DoPanic both count as failures
+
+	var sampleCount uint64
+	for _, mf := range families {
+		if mf.GetName() != "hanzo_agents_service_method_duration_seconds" {
+			continue
+		}
+		for _, m := range mf.Metric {
+			sampleCount += m.GetHistogram().GetSampleCount()
+		}
+	}
+	require.Equal(t, uint64(3), sampleCount)
+}
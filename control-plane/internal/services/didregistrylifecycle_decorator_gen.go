@@ -0,0 +1,35 @@
+// Code generated by servicedecorator; DO NOT EDIT.
+
+package services
+
+import (
+	"context"
+
+	"github.com/hanzoai/agents/control-plane/internal/middleware"
+)
+
+// DIDRegistryLifecycleDecorator wraps a DIDRegistryLifecycle implementation with panic recovery,
+// structured error logging and latency/error metrics for every method, via
+// middleware.Recoverer. Regenerate with 'go generate' after adding or
+// changing methods on DIDRegistryLifecycle.
+type DIDRegistryLifecycleDecorator struct {
+	inner     DIDRegistryLifecycle
+	recoverer *middleware.Recoverer
+}
+
+// NewInstrumentedDIDRegistryLifecycle wraps inner so every call is recovered, logged
+// and measured under the "DIDRegistryLifecycle" service name.
+func NewInstrumentedDIDRegistryLifecycle(inner DIDRegistryLifecycle, metrics *middleware.Metrics) DIDRegistryLifecycle {
+	return &DIDRegistryLifecycleDecorator{
+		inner:     inner,
+		recoverer: middleware.NewRecoverer("DIDRegistryLifecycle", metrics),
+	}
+}
+
+func (d *DIDRegistryLifecycleDecorator) Initialize(ctx context.Context) (err error) {
+	err = d.recoverer.Call(ctx, "Initialize", func() error {
+		return d.inner.Initialize(ctx)
+	})
+	return
+}
+
@@ -0,0 +1,35 @@
+// Code generated by servicedecorator; DO NOT EDIT.
+
+package services
+
+import (
+	"context"
+
+	"github.com/hanzoai/agents/control-plane/internal/middleware"
+)
+
+// DIDServiceLifecycleDecorator wraps a DIDServiceLifecycle implementation with panic recovery,
+// structured error logging and latency/error metrics for every method, via
+// middleware.Recoverer. Regenerate with 'go generate' after adding or
+// changing methods on DIDServiceLifecycle.
+type DIDServiceLifecycleDecorator struct {
+	inner     DIDServiceLifecycle
+	recoverer *middleware.Recoverer
+}
+
+// NewInstrumentedDIDServiceLifecycle wraps inner so every call is recovered, logged
+// and measured under the "DIDServiceLifecycle" service name.
+func NewInstrumentedDIDServiceLifecycle(inner DIDServiceLifecycle, metrics *middleware.Metrics) DIDServiceLifecycle {
+	return &DIDServiceLifecycleDecorator{
+		inner:     inner,
+		recoverer: middleware.NewRecoverer("DIDServiceLifecycle", metrics),
+	}
+}
+
+func (d *DIDServiceLifecycleDecorator) Initialize(ctx context.Context, p0 string) (err error) {
+	err = d.recoverer.Call(ctx, "Initialize", func() error {
+		return d.inner.Initialize(ctx, p0)
+	})
+	return
+}
+
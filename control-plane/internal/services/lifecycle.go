@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+
+	"github.com/hanzoai/agents/control-plane/internal/middleware"
+)
+
+// DIDRegistryLifecycle, DIDServiceLifecycle and VCServiceLifecycle extract
+// just the initialization surface that application.CreateServiceContainer
+// calls on *DIDRegistry, *DIDService and *VCService, so middleware/gen can
+// produce a panic-recovering, instrumented decorator for it. The full
+// service types carry many more exported methods once cfg.Features.DID is
+// wired end to end; those aren't covered yet (see the NOTE in
+// application/container.go), but every call this container itself makes
+// now goes through a generated decorator instead of an ad hoc
+// middleware.Recoverer.Call.
+
+//go:generate go run github.com/hanzoai/agents/control-plane/internal/middleware/gen -type DIDRegistryLifecycle -out didregistrylifecycle_decorator_gen.go
+
+// DIDRegistryLifecycle is the subset of *DIDRegistry's exported surface
+// used during container construction.
+type DIDRegistryLifecycle interface {
+	Initialize(ctx context.Context) error
+}
+
+//go:generate go run github.com/hanzoai/agents/control-plane/internal/middleware/gen -type DIDServiceLifecycle -out didservicelifecycle_decorator_gen.go
+
+// DIDServiceLifecycle is the subset of *DIDService's exported surface used
+// during container construction.
+type DIDServiceLifecycle interface {
+	Initialize(ctx context.Context, serverID string) error
+}
+
+//go:generate go run github.com/hanzoai/agents/control-plane/internal/middleware/gen -type VCServiceLifecycle -out vcservicelifecycle_decorator_gen.go
+
+// VCServiceLifecycle is the subset of *VCService's exported surface used
+// during container construction.
+type VCServiceLifecycle interface {
+	Initialize(ctx context.Context) error
+}
+
+// didRegistryLifecycle, didServiceLifecycle and vcServiceLifecycle adapt
+// the real services' context-less Initialize methods to the ctx-first
+// signatures middleware/gen's generated decorators expect, without
+// changing the real types themselves.
+type didRegistryLifecycle struct{ inner *DIDRegistry }
+
+func (a didRegistryLifecycle) Initialize(ctx context.Context) error { return a.inner.Initialize() }
+
+type didServiceLifecycle struct{ inner *DIDService }
+
+func (a didServiceLifecycle) Initialize(ctx context.Context, serverID string) error {
+	return a.inner.Initialize(serverID)
+}
+
+type vcServiceLifecycle struct{ inner *VCService }
+
+func (a vcServiceLifecycle) Initialize(ctx context.Context) error { return a.inner.Initialize() }
+
+// WrapDIDRegistryLifecycle, WrapDIDServiceLifecycle and WrapVCServiceLifecycle
+// return inner wrapped in a panic-recovering, instrumented decorator
+// generated from the Lifecycle interfaces above, for application.CreateServiceContainer
+// to call instead of invoking Initialize directly.
+func WrapDIDRegistryLifecycle(inner *DIDRegistry, metrics *middleware.Metrics) DIDRegistryLifecycle {
+	return NewInstrumentedDIDRegistryLifecycle(didRegistryLifecycle{inner}, metrics)
+}
+
+func WrapDIDServiceLifecycle(inner *DIDService, metrics *middleware.Metrics) DIDServiceLifecycle {
+	return NewInstrumentedDIDServiceLifecycle(didServiceLifecycle{inner}, metrics)
+}
+
+func WrapVCServiceLifecycle(inner *VCService, metrics *middleware.Metrics) VCServiceLifecycle {
+	return NewInstrumentedVCServiceLifecycle(vcServiceLifecycle{inner}, metrics)
+}
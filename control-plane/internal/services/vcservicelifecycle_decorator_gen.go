@@ -0,0 +1,35 @@
+// Code generated by servicedecorator; DO NOT EDIT.
+
+package services
+
+import (
+	"context"
+
+	"github.com/hanzoai/agents/control-plane/internal/middleware"
+)
+
+// VCServiceLifecycleDecorator wraps a VCServiceLifecycle implementation with panic recovery,
+// structured error logging and latency/error metrics for every method, via
+// middleware.Recoverer. Regenerate with 'go generate' after adding or
+// changing methods on VCServiceLifecycle.
+type VCServiceLifecycleDecorator struct {
+	inner     VCServiceLifecycle
+	recoverer *middleware.Recoverer
+}
+
+// NewInstrumentedVCServiceLifecycle wraps inner so every call is recovered, logged
+// and measured under the "VCServiceLifecycle" service name.
+func NewInstrumentedVCServiceLifecycle(inner VCServiceLifecycle, metrics *middleware.Metrics) VCServiceLifecycle {
+	return &VCServiceLifecycleDecorator{
+		inner:     inner,
+		recoverer: middleware.NewRecoverer("VCServiceLifecycle", metrics),
+	}
+}
+
+func (d *VCServiceLifecycleDecorator) Initialize(ctx context.Context) (err error) {
+	err = d.recoverer.Call(ctx, "Initialize", func() error {
+		return d.inner.Initialize(ctx)
+	})
+	return
+}
+
@@ -0,0 +1,382 @@
+// hanzo-agents/internal/utils/diskusage.go
+package utils
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrQuotaExceeded is returned by DiskUsageMonitor.CheckQuota, and so by
+// writers that call it (the quota-enforcing FileSystemAdapter in
+// infrastructure/storage), once a write would bring a category to or over
+// its hard quota. Workflow execution storage (StoreWorkflowExecution) does
+// not call CheckQuota and never returns it. Callers should surface it to the user
+// rather than retry; the write will keep failing until cleanup frees space
+// or the quota is raised.
+type ErrQuotaExceeded struct {
+	Category string
+	Used     int64
+	Limit    int64
+}
+
+func (e *ErrQuotaExceeded) Error() string {
+	return fmt.Sprintf("utils: %s disk quota exceeded: %d/%d bytes used", e.Category, e.Used, e.Limit)
+}
+
+// Quota-tracked categories. These line up with the directories returned by
+// GetHanzoAgentsDataDirectories that are expected to grow unboundedly over
+// the life of an installation.
+const (
+	CategoryDatabase = "database"
+	CategoryVCs      = "vcs"
+	CategoryPayloads = "payloads"
+	CategoryLogs     = "logs"
+)
+
+// CategoryQuota is the soft/hard byte thresholds for one tracked category.
+// A zero Hard means "unlimited" - DiskUsageMonitor never refuses writes for
+// that category. A zero Soft disables proactive cleanup for it.
+type CategoryQuota struct {
+	SoftBytes int64
+	HardBytes int64
+}
+
+// CategoryUsage is a point-in-time measurement for one tracked category.
+type CategoryUsage struct {
+	Path       string
+	BytesUsed  int64
+	MeasuredAt time.Time
+}
+
+// DiskUsageMonitor periodically stats the HanzoAgents data directories and
+// tracks bytes-used per category plus filesystem free space, enforcing the
+// configured soft/hard quotas. Callers read the latest measurement with
+// Snapshot; Start runs the periodic poll in the background.
+type DiskUsageMonitor struct {
+	dirs    *DataDirectories
+	quotas  map[string]CategoryQuota
+	onSoft  func(category string) error
+	onError func(category string, err error)
+
+	mu       sync.RWMutex
+	usage    map[string]CategoryUsage
+	freeDisk DiskFreeSpace
+}
+
+// DiskFreeSpace reports free/total bytes for the filesystem backing the
+// HanzoAgents home directory.
+type DiskFreeSpace struct {
+	FreeBytes  uint64
+	TotalBytes uint64
+}
+
+// NewDiskUsageMonitor builds a monitor over dirs' tracked categories.
+// quotas may omit any category; omitted categories have no enforced limit.
+// onSoft is invoked (synchronously, from within Poll) the first
+// time a category's soft quota is exceeded on a given poll so the caller
+// can trigger cleanup/rotation; it is not called again for that category
+// until usage drops back under the soft quota and trips it again.
+func NewDiskUsageMonitor(dirs *DataDirectories, quotas map[string]CategoryQuota, onSoft func(category string) error, onError func(category string, err error)) *DiskUsageMonitor {
+	return &DiskUsageMonitor{
+		dirs:    dirs,
+		quotas:  quotas,
+		onSoft:  onSoft,
+		onError: onError,
+		usage:   make(map[string]CategoryUsage),
+	}
+}
+
+// categoryPaths returns the directories tracked per category.
+func (m *DiskUsageMonitor) categoryPaths() map[string]string {
+	return map[string]string{
+		CategoryDatabase: m.dirs.DatabaseDir,
+		CategoryVCs:      m.dirs.VCsDir,
+		CategoryPayloads: m.dirs.PayloadsDir,
+		CategoryLogs:     m.dirs.LogsDir,
+	}
+}
+
+// Poll measures every tracked category once, updates the latest snapshot,
+// and fires onSoft for any category whose usage is at or above its soft
+// quota and was below it (or not yet measured) as of the previous poll. It
+// returns the categories (if any) whose usage is at or above the hard quota.
+func (m *DiskUsageMonitor) Poll() (overHard []string, err error) {
+	softTripped := make(map[string]bool)
+
+	m.mu.Lock()
+	alreadyOverSoft := make(map[string]bool, len(m.usage))
+	for category, usage := range m.usage {
+		if q, ok := m.quotas[category]; ok && q.SoftBytes > 0 && usage.BytesUsed >= q.SoftBytes {
+			alreadyOverSoft[category] = true
+		}
+	}
+	m.mu.Unlock()
+
+	now := time.Now()
+	newUsage := make(map[string]CategoryUsage, len(m.categoryPaths()))
+
+	for category, path := range m.categoryPaths() {
+		used, statErr := dirSizeBytes(path)
+		if statErr != nil {
+			if m.onError != nil {
+				m.onError(category, statErr)
+			}
+			continue
+		}
+
+		newUsage[category] = CategoryUsage{Path: path, BytesUsed: used, MeasuredAt: now}
+
+		if q, ok := m.quotas[category]; ok {
+			if q.HardBytes > 0 && used >= q.HardBytes {
+				overHard = append(overHard, category)
+			}
+			if q.SoftBytes > 0 && used >= q.SoftBytes && !alreadyOverSoft[category] {
+				softTripped[category] = true
+			}
+		}
+	}
+
+	free, totalErr := statFreeSpace(m.dirs.HanzoAgentsHome)
+	m.mu.Lock()
+	for category, usage := range newUsage {
+		m.usage[category] = usage
+	}
+	if totalErr == nil {
+		m.freeDisk = free
+	}
+	m.mu.Unlock()
+
+	if totalErr != nil && m.onError != nil {
+		m.onError("disk", totalErr)
+	}
+
+	for category := range softTripped {
+		if m.onSoft == nil {
+			continue
+		}
+		if softErr := m.onSoft(category); softErr != nil && m.onError != nil {
+			m.onError(category, softErr)
+		}
+	}
+
+	return overHard, nil
+}
+
+// Start polls every interval until ctx is cancelled.
+func (m *DiskUsageMonitor) Start(ctx context.Context, interval time.Duration) {
+	// Poll once synchronously before returning: CheckQuota only refuses
+	// writes against the last poll's usage, and time.Ticker doesn't fire
+	// until interval has elapsed, so without this a hard quota couldn't
+	// trip for the whole first interval after Start -- long enough to
+	// outlast most CLI invocations entirely.
+	_, _ = m.Poll()
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_, _ = m.Poll()
+			}
+		}
+	}()
+}
+
+// Snapshot returns the most recent per-category usage and free-space
+// measurement. It does not poll; call Poll first (or start Start) to
+// populate it.
+func (m *DiskUsageMonitor) Snapshot() (map[string]CategoryUsage, DiskFreeSpace) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	usage := make(map[string]CategoryUsage, len(m.usage))
+	for k, v := range m.usage {
+		usage[k] = v
+	}
+	return usage, m.freeDisk
+}
+
+// CheckQuota returns *ErrQuotaExceeded if category is at or over its hard
+// quota as of the last poll, based on a write of additionalBytes. Write
+// paths (payload storage, workflow execution storage) should call this
+// before persisting new data.
+func (m *DiskUsageMonitor) CheckQuota(category string, additionalBytes int64) error {
+	q, ok := m.quotas[category]
+	if !ok || q.HardBytes <= 0 {
+		return nil
+	}
+
+	m.mu.RLock()
+	usage, tracked := m.usage[category]
+	m.mu.RUnlock()
+	if !tracked {
+		return nil
+	}
+
+	projected := usage.BytesUsed + additionalBytes
+	if projected >= q.HardBytes {
+		return &ErrQuotaExceeded{Category: category, Used: projected, Limit: q.HardBytes}
+	}
+	return nil
+}
+
+// RotateLogs gzip-compresses every "*.log" file in dir except the most
+// recently modified one (the active log file) into a ".log.gz" sibling,
+// removing the uncompressed original, and is meant to be called from
+// DiskUsageMonitor's onSoft callback for CategoryLogs so a soft quota trip
+// reclaims space instead of only being logged. Files that are already
+// gzipped, or a dir that doesn't exist yet, are left alone. It returns the
+// number of files rotated.
+func RotateLogs(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var activeLog string
+	var activeModTime time.Time
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".log" {
+			continue
+		}
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			continue
+		}
+		if activeLog == "" || info.ModTime().After(activeModTime) {
+			activeLog = entry.Name()
+			activeModTime = info.ModTime()
+		}
+	}
+
+	rotated := 0
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".log" || entry.Name() == activeLog {
+			continue
+		}
+		if err := gzipAndRemove(filepath.Join(dir, entry.Name())); err != nil {
+			return rotated, fmt.Errorf("utils: rotating log %q: %w", entry.Name(), err)
+		}
+		rotated++
+	}
+	return rotated, nil
+}
+
+// gzipAndRemove compresses path into path+".gz" and removes the original
+// once the compressed copy is fully written.
+func gzipAndRemove(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dstPath := path + ".gz"
+	dst, err := os.OpenFile(dstPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(dst)
+	if _, err := io.Copy(gw, src); err != nil {
+		gw.Close()
+		dst.Close()
+		os.Remove(dstPath)
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		dst.Close()
+		os.Remove(dstPath)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(dstPath)
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// ServeHTTP exposes the latest snapshot as JSON, so it can be mounted
+// directly on the service's admin/metrics HTTP mux.
+func (m *DiskUsageMonitor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	usage, free := m.Snapshot()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(struct {
+		Categories map[string]CategoryUsage `json:"categories"`
+		Disk       DiskFreeSpace            `json:"disk"`
+	}{Categories: usage, Disk: free})
+}
+
+// Describe and Collect implement prometheus.Collector so the monitor's
+// latest snapshot can be scraped alongside the rest of the service's
+// metrics.
+var (
+	diskUsageBytesDesc = prometheus.NewDesc(
+		"hanzo_agents_disk_usage_bytes", "Bytes used by a tracked HanzoAgents data category.",
+		[]string{"category"}, nil,
+	)
+	diskFreeBytesDesc = prometheus.NewDesc(
+		"hanzo_agents_disk_free_bytes", "Free bytes on the filesystem backing the HanzoAgents home directory.",
+		nil, nil,
+	)
+)
+
+func (m *DiskUsageMonitor) Describe(ch chan<- *prometheus.Desc) {
+	ch <- diskUsageBytesDesc
+	ch <- diskFreeBytesDesc
+}
+
+func (m *DiskUsageMonitor) Collect(ch chan<- prometheus.Metric) {
+	usage, free := m.Snapshot()
+	for category, u := range usage {
+		ch <- prometheus.MustNewConstMetric(diskUsageBytesDesc, prometheus.GaugeValue, float64(u.BytesUsed), category)
+	}
+	ch <- prometheus.MustNewConstMetric(diskFreeBytesDesc, prometheus.GaugeValue, float64(free.FreeBytes))
+}
+
+// dirSizeBytes recursively sums file sizes under path. A missing directory
+// is treated as zero bytes rather than an error, since a category
+// directory may not exist yet on a fresh install.
+func dirSizeBytes(path string) (int64, error) {
+	var total int64
+
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			if os.IsNotExist(walkErr) {
+				return nil
+			}
+			return walkErr
+		}
+		if info == nil {
+			return nil
+		}
+		if !info.IsDir() {
+			total += info.Size()
+		}
+		return nil
+	})
+
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	return total, err
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testDataDirectories(t *testing.T) *DataDirectories {
+	t.Helper()
+	root := t.TempDir()
+
+	dirs := &DataDirectories{
+		Layout:          LayoutLegacy,
+		HanzoAgentsHome: root,
+		DatabaseDir:     filepath.Join(root, "data"),
+		VCsDir:          filepath.Join(root, "data", "vcs"),
+		PayloadsDir:     filepath.Join(root, "data", "payloads"),
+		LogsDir:         filepath.Join(root, "logs"),
+	}
+
+	for _, dir := range []string{dirs.DatabaseDir, dirs.VCsDir, dirs.PayloadsDir, dirs.LogsDir} {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+	}
+
+	return dirs
+}
+
+func writeSizedFile(t *testing.T, path string, size int) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0644))
+}
+
+func TestDiskUsageMonitor_PollMeasuresCategories(t *testing.T) {
+	dirs := testDataDirectories(t)
+	writeSizedFile(t, filepath.Join(dirs.PayloadsDir, "p1.bin"), 1024)
+	writeSizedFile(t, filepath.Join(dirs.LogsDir, "app.log"), 512)
+
+	mon := NewDiskUsageMonitor(dirs, nil, nil, nil)
+	_, err := mon.Poll()
+	require.NoError(t, err)
+
+	usage, _ := mon.Snapshot()
+	require.Equal(t, int64(1024), usage[CategoryPayloads].BytesUsed)
+	require.Equal(t, int64(512), usage[CategoryLogs].BytesUsed)
+}
+
+func TestDiskUsageMonitor_SoftQuotaTriggersCallback(t *testing.T) {
+	dirs := testDataDirectories(t)
+	writeSizedFile(t, filepath.Join(dirs.PayloadsDir, "p1.bin"), 2048)
+
+	var triggered []string
+	quotas := map[string]CategoryQuota{
+		CategoryPayloads: {SoftBytes: 1024, HardBytes: 4096},
+	}
+	mon := NewDiskUsageMonitor(dirs, quotas, func(category string) error {
+		triggered = append(triggered, category)
+		return nil
+	}, nil)
+
+	overHard, err := mon.Poll()
+	require.NoError(t, err)
+	require.Empty(t, overHard)
+	require.Equal(t, []string{CategoryPayloads}, triggered)
+
+	// A second poll with usage still over the soft quota should not
+	// re-trigger cleanup until it drops back below and trips again.
+	overHard, err = mon.Poll()
+	require.NoError(t, err)
+	require.Empty(t, overHard)
+	require.Equal(t, []string{CategoryPayloads}, triggered)
+}
+
+func TestDiskUsageMonitor_StartPollsImmediately(t *testing.T) {
+	dirs := testDataDirectories(t)
+	writeSizedFile(t, filepath.Join(dirs.PayloadsDir, "p1.bin"), 4096)
+
+	quotas := map[string]CategoryQuota{CategoryPayloads: {HardBytes: 1024}}
+	mon := NewDiskUsageMonitor(dirs, quotas, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mon.Start(ctx, time.Hour)
+
+	// CheckQuota must see usage from Start's own initial poll, not only
+	// from whatever poll eventually fires on the (here, very long) ticker
+	// interval.
+	err := mon.CheckQuota(CategoryPayloads, 1)
+	require.Error(t, err)
+}
+
+func TestDiskUsageMonitor_HardQuotaRefusesWrites(t *testing.T) {
+	dirs := testDataDirectories(t)
+	writeSizedFile(t, filepath.Join(dirs.PayloadsDir, "p1.bin"), 4096)
+
+	quotas := map[string]CategoryQuota{
+		CategoryPayloads: {HardBytes: 4096},
+	}
+	mon := NewDiskUsageMonitor(dirs, quotas, nil, nil)
+
+	overHard, err := mon.Poll()
+	require.NoError(t, err)
+	require.Equal(t, []string{CategoryPayloads}, overHard)
+
+	err = mon.CheckQuota(CategoryPayloads, 1)
+	require.Error(t, err)
+
+	var quotaErr *ErrQuotaExceeded
+	require.ErrorAs(t, err, &quotaErr)
+	require.Equal(t, CategoryPayloads, quotaErr.Category)
+}
+
+func TestDiskUsageMonitor_UnderQuotaAllowsWrites(t *testing.T) {
+	dirs := testDataDirectories(t)
+	writeSizedFile(t, filepath.Join(dirs.PayloadsDir, "p1.bin"), 100)
+
+	quotas := map[string]CategoryQuota{
+		CategoryPayloads: {HardBytes: 4096},
+	}
+	mon := NewDiskUsageMonitor(dirs, quotas, nil, nil)
+
+	_, err := mon.Poll()
+	require.NoError(t, err)
+	require.NoError(t, mon.CheckQuota(CategoryPayloads, 100))
+}
+
+func TestRotateLogs_CompressesAllButActiveLog(t *testing.T) {
+	dir := t.TempDir()
+
+	old := filepath.Join(dir, "old.log")
+	writeSizedFile(t, old, 256)
+	oldTime := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(old, oldTime, oldTime))
+
+	active := filepath.Join(dir, "active.log")
+	writeSizedFile(t, active, 64)
+
+	rotated, err := RotateLogs(dir)
+	require.NoError(t, err)
+	require.Equal(t, 1, rotated)
+
+	_, err = os.Stat(old)
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(old + ".gz")
+	require.NoError(t, err)
+
+	_, err = os.Stat(active)
+	require.NoError(t, err, "the most recently written log must not be rotated away")
+}
+
+func TestRotateLogs_MissingDirIsNoop(t *testing.T) {
+	rotated, err := RotateLogs(filepath.Join(t.TempDir(), "does-not-exist"))
+	require.NoError(t, err)
+	require.Equal(t, 0, rotated)
+}
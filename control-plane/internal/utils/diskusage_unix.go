@@ -0,0 +1,20 @@
+//go:build !windows
+
+package utils
+
+import "golang.org/x/sys/unix"
+
+// statFreeSpace reports free/total bytes for the filesystem containing
+// path, via unix.Statfs.
+func statFreeSpace(path string) (DiskFreeSpace, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return DiskFreeSpace{}, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+	return DiskFreeSpace{
+		FreeBytes:  stat.Bavail * blockSize,
+		TotalBytes: stat.Blocks * blockSize,
+	}, nil
+}
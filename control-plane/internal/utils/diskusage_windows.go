@@ -0,0 +1,36 @@
+//go:build windows
+
+package utils
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procGetDiskFreeSpaceExW = kernel32.NewProc("GetDiskFreeSpaceExW")
+)
+
+// statFreeSpace reports free/total bytes for the volume containing path,
+// via the Win32 GetDiskFreeSpaceExW API.
+func statFreeSpace(path string) (DiskFreeSpace, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return DiskFreeSpace{}, err
+	}
+
+	var freeBytesAvailable, totalBytes, totalFreeBytes uint64
+
+	ret, _, callErr := procGetDiskFreeSpaceExW.Call(
+		uintptr(unsafe.Pointer(pathPtr)),
+		uintptr(unsafe.Pointer(&freeBytesAvailable)),
+		uintptr(unsafe.Pointer(&totalBytes)),
+		uintptr(unsafe.Pointer(&totalFreeBytes)),
+	)
+	if ret == 0 {
+		return DiskFreeSpace{}, callErr
+	}
+
+	return DiskFreeSpace{FreeBytes: freeBytesAvailable, TotalBytes: totalBytes}, nil
+}
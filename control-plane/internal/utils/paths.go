@@ -1,14 +1,35 @@
 package utils
 
 import (
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
 )
 
+// Layout identifies how HanzoAgents data directories are arranged on disk.
+type Layout string
+
+const (
+	// LayoutLegacy keeps every HanzoAgents directory under a single
+	// ~/.hanzo-agents (or $HANZO_AGENTS_HOME) tree, as originally shipped.
+	LayoutLegacy Layout = "legacy"
+	// LayoutXDG splits HanzoAgents state across the XDG Base Directory
+	// locations ($XDG_DATA_HOME, $XDG_CONFIG_HOME, $XDG_CACHE_HOME,
+	// $XDG_STATE_HOME), each under a "hanzo-agents" subdirectory.
+	LayoutXDG Layout = "xdg"
+)
+
+// layoutEnvVar lets users pin the directory layout instead of relying on the
+// platform default, e.g. HANZO_AGENTS_LAYOUT=legacy to keep the old tree.
+const layoutEnvVar = "HANZO_AGENTS_LAYOUT"
+
 // DataDirectories holds all the standardized paths for HanzoAgents data storage
 type DataDirectories struct {
-	HanzoAgentsHome   string
+	Layout Layout
+
+	HanzoAgentsHome  string
 	DataDir          string
 	DatabaseDir      string
 	KeysDir          string
@@ -21,24 +42,56 @@ type DataDirectories struct {
 	ConfigDir        string
 	TempDir          string
 	PayloadsDir      string
+
+	// RegistryDir is the directory containing installed.json. It tracks
+	// ConfigDir under the xdg layout and HanzoAgentsHome under the legacy
+	// layout, matching where each layout has historically kept it.
+	RegistryDir string
+}
+
+// resolveLayout determines which directory layout to use, honoring the
+// HANZO_AGENTS_LAYOUT override and falling back to a platform default:
+// xdg on Linux, legacy everywhere else (macOS support can be enabled the
+// same way once its Library/ conventions are mapped).
+func resolveLayout() Layout {
+	switch Layout(os.Getenv(layoutEnvVar)) {
+	case LayoutLegacy:
+		return LayoutLegacy
+	case LayoutXDG:
+		return LayoutXDG
+	}
+
+	if runtime.GOOS == "linux" {
+		return LayoutXDG
+	}
+	return LayoutLegacy
 }
 
 // GetHanzoAgentsDataDirectories returns the standardized data directories for HanzoAgents
 // It respects environment variables and provides sensible defaults
 func GetHanzoAgentsDataDirectories() (*DataDirectories, error) {
-	// Determine HanzoAgents home directory
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if resolveLayout() == LayoutXDG {
+		return xdgDataDirectories(homeDir), nil
+	}
+	return legacyDataDirectories(homeDir)
+}
+
+// legacyDataDirectories reproduces the original single-tree layout rooted at
+// $HANZO_AGENTS_HOME (or ~/.hanzo-agents).
+func legacyDataDirectories(homeDir string) (*DataDirectories, error) {
 	hanzoAgentsHome := os.Getenv("HANZO_AGENTS_HOME")
 	if hanzoAgentsHome == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return nil, err
-		}
 		hanzoAgentsHome = filepath.Join(homeDir, ".hanzo-agents")
 	}
 
-	// Create the data directories structure
-	dirs := &DataDirectories{
-		HanzoAgentsHome:   hanzoAgentsHome,
+	return &DataDirectories{
+		Layout:           LayoutLegacy,
+		HanzoAgentsHome:  hanzoAgentsHome,
 		DataDir:          filepath.Join(hanzoAgentsHome, "data"),
 		DatabaseDir:      filepath.Join(hanzoAgentsHome, "data"),
 		KeysDir:          filepath.Join(hanzoAgentsHome, "data", "keys"),
@@ -51,9 +104,56 @@ func GetHanzoAgentsDataDirectories() (*DataDirectories, error) {
 		ConfigDir:        filepath.Join(hanzoAgentsHome, "config"),
 		TempDir:          filepath.Join(hanzoAgentsHome, "temp"),
 		PayloadsDir:      filepath.Join(hanzoAgentsHome, "data", "payloads"),
+		RegistryDir:      hanzoAgentsHome,
+	}, nil
+}
+
+// xdgDataDirectories splits HanzoAgents state across the XDG Base Directory
+// locations, each falling back to the spec's documented default when the
+// corresponding environment variable is unset:
+//
+//	$XDG_DATA_HOME   -> ~/.local/share  (database, vcs, did registries, keys, agents)
+//	$XDG_CONFIG_HOME -> ~/.config       (config, installed.json)
+//	$XDG_CACHE_HOME  -> ~/.cache        (temp, payloads)
+//	$XDG_STATE_HOME  -> ~/.local/state  (logs)
+func xdgDataDirectories(homeDir string) *DataDirectories {
+	dataHome := xdgEnvOrDefault("XDG_DATA_HOME", filepath.Join(homeDir, ".local", "share"))
+	configHome := xdgEnvOrDefault("XDG_CONFIG_HOME", filepath.Join(homeDir, ".config"))
+	cacheHome := xdgEnvOrDefault("XDG_CACHE_HOME", filepath.Join(homeDir, ".cache"))
+	stateHome := xdgEnvOrDefault("XDG_STATE_HOME", filepath.Join(homeDir, ".local", "state"))
+
+	dataRoot := filepath.Join(dataHome, "hanzo-agents")
+	configRoot := filepath.Join(configHome, "hanzo-agents")
+	cacheRoot := filepath.Join(cacheHome, "hanzo-agents")
+	stateRoot := filepath.Join(stateHome, "hanzo-agents")
+
+	return &DataDirectories{
+		Layout:           LayoutXDG,
+		HanzoAgentsHome:  dataRoot,
+		DataDir:          dataRoot,
+		DatabaseDir:      dataRoot,
+		KeysDir:          filepath.Join(dataRoot, "keys"),
+		DIDRegistriesDir: filepath.Join(dataRoot, "did_registries"),
+		VCsDir:           filepath.Join(dataRoot, "vcs"),
+		VCsExecutionsDir: filepath.Join(dataRoot, "vcs", "executions"),
+		VCsWorkflowsDir:  filepath.Join(dataRoot, "vcs", "workflows"),
+		AgentsDir:        filepath.Join(dataRoot, "agents"),
+		LogsDir:          stateRoot,
+		ConfigDir:        configRoot,
+		TempDir:          filepath.Join(cacheRoot, "temp"),
+		PayloadsDir:      filepath.Join(cacheRoot, "payloads"),
+		RegistryDir:      configRoot,
 	}
+}
 
-	return dirs, nil
+// xdgEnvOrDefault returns the named environment variable, or fallback if it
+// is unset or empty, matching the XDG Base Directory spec's handling of
+// blank values.
+func xdgEnvOrDefault(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
 }
 
 // EnsureDataDirectories creates all necessary HanzoAgents data directories
@@ -63,6 +163,16 @@ func EnsureDataDirectories() (*DataDirectories, error) {
 		return nil, err
 	}
 
+	// Migrate any pre-existing legacy tree before creating XDG directories:
+	// migrateEntry refuses to clobber a destination that already exists, so
+	// running this after MkdirAll below would find every destination
+	// already present (created empty) and skip every move.
+	if dirs.Layout == LayoutXDG {
+		if err := MigrateLegacyToXDG(dirs); err != nil {
+			return nil, err
+		}
+	}
+
 	// Create all directories with appropriate permissions
 	directoriesToCreate := []string{
 		dirs.HanzoAgentsHome,
@@ -78,6 +188,7 @@ func EnsureDataDirectories() (*DataDirectories, error) {
 		dirs.ConfigDir,
 		dirs.TempDir,
 		dirs.PayloadsDir,
+		dirs.RegistryDir,
 	}
 
 	for _, dir := range directoriesToCreate {
@@ -101,6 +212,142 @@ func EnsureDataDirectories() (*DataDirectories, error) {
 	return dirs, nil
 }
 
+// MigrateLegacyToXDG moves each subdirectory of a pre-existing
+// ~/.hanzo-agents (or $HANZO_AGENTS_HOME) tree into its XDG-correct location
+// described by dirs. It is a no-op if no legacy tree exists, and never
+// touches a destination that already exists (even an empty one) so a second run (or a run
+// after the user has started using the new layout) is always safe.
+func MigrateLegacyToXDG(dirs *DataDirectories) error {
+	if dirs.Layout != LayoutXDG {
+		return fmt.Errorf("utils: MigrateLegacyToXDG requires the xdg layout, got %q", dirs.Layout)
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	legacyHome := os.Getenv("HANZO_AGENTS_HOME")
+	if legacyHome == "" {
+		legacyHome = filepath.Join(homeDir, ".hanzo-agents")
+	}
+
+	if _, err := os.Stat(legacyHome); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	moves := []struct {
+		from string
+		to   string
+	}{
+		{filepath.Join(legacyHome, "data", "keys"), dirs.KeysDir},
+		{filepath.Join(legacyHome, "data", "did_registries"), dirs.DIDRegistriesDir},
+		{filepath.Join(legacyHome, "data", "vcs"), dirs.VCsDir},
+		{filepath.Join(legacyHome, "data", "payloads"), dirs.PayloadsDir},
+		{filepath.Join(legacyHome, "agents"), dirs.AgentsDir},
+		{filepath.Join(legacyHome, "logs"), dirs.LogsDir},
+		{filepath.Join(legacyHome, "config"), dirs.ConfigDir},
+		{filepath.Join(legacyHome, "temp"), dirs.TempDir},
+		{filepath.Join(legacyHome, "installed.json"), filepath.Join(dirs.RegistryDir, "installed.json")},
+		{filepath.Join(legacyHome, "data", "hanzo-agents.db"), filepath.Join(dirs.DatabaseDir, "hanzo-agents.db")},
+		{filepath.Join(legacyHome, "data", "hanzo-agents.bolt"), filepath.Join(dirs.DatabaseDir, "hanzo-agents.bolt")},
+	}
+
+	for _, m := range moves {
+		if err := migrateEntry(m.from, m.to); err != nil {
+			return fmt.Errorf("utils: migrating %s to %s: %w", m.from, m.to, err)
+		}
+	}
+
+	return nil
+}
+
+// migrateEntry moves a single legacy file or directory to its new location.
+// It does nothing if the source is absent, and refuses to clobber an
+// existing destination so a partially migrated tree never loses data.
+func migrateEntry(from, to string) error {
+	info, err := os.Stat(from)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(to); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+
+	// Rename can fail across filesystem boundaries (e.g. a bind-mounted
+	// XDG_DATA_HOME); fall back to a copy-then-remove.
+	if info.IsDir() {
+		return copyDir(from, to)
+	}
+	return copyFile(from, to)
+}
+
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	dst, err := os.OpenFile(to, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode())
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return err
+	}
+
+	return os.Remove(from)
+}
+
+func copyDir(from, to string) error {
+	if err := os.MkdirAll(to, 0755); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(from)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		src := filepath.Join(from, entry.Name())
+		dst := filepath.Join(to, entry.Name())
+		if entry.IsDir() {
+			if err := copyDir(src, dst); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := copyFile(src, dst); err != nil {
+			return err
+		}
+	}
+
+	return os.RemoveAll(from)
+}
+
 // GetDatabasePath returns the path to the main HanzoAgents database
 func GetDatabasePath() (string, error) {
 	dirs, err := GetHanzoAgentsDataDirectories()
@@ -125,7 +372,7 @@ func GetAgentRegistryPath() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(dirs.HanzoAgentsHome, "installed.json"), nil
+	return filepath.Join(dirs.RegistryDir, "installed.json"), nil
 }
 
 // GetConfigPath returns the path to a configuration file
@@ -174,6 +421,7 @@ func GetPlatformSpecificPaths() map[string]string {
 		paths["xdg_config_home"] = os.Getenv("XDG_CONFIG_HOME")
 		paths["xdg_data_home"] = os.Getenv("XDG_DATA_HOME")
 		paths["xdg_cache_home"] = os.Getenv("XDG_CACHE_HOME")
+		paths["xdg_state_home"] = os.Getenv("XDG_STATE_HOME")
 	}
 
 	return paths
@@ -0,0 +1,236 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// withEnv sets env vars for the duration of the test and restores the
+// previous values (including "unset") on cleanup.
+func withEnv(t *testing.T, vars map[string]string) {
+	t.Helper()
+	for k, v := range vars {
+		prev, had := os.LookupEnv(k)
+		os.Setenv(k, v)
+		t.Cleanup(func() {
+			if had {
+				os.Setenv(k, prev)
+			} else {
+				os.Unsetenv(k)
+			}
+		})
+	}
+}
+
+func TestGetHanzoAgentsDataDirectories_XDGCombinations(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("xdg layout is Linux-only for now")
+	}
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	cases := []struct {
+		name       string
+		env        map[string]string
+		wantData   string
+		wantConfig string
+		wantCache  string
+		wantState  string
+	}{
+		{
+			name: "all set",
+			env: map[string]string{
+				"XDG_DATA_HOME":   "/tmp/xdg-data",
+				"XDG_CONFIG_HOME": "/tmp/xdg-config",
+				"XDG_CACHE_HOME":  "/tmp/xdg-cache",
+				"XDG_STATE_HOME":  "/tmp/xdg-state",
+			},
+			wantData:   "/tmp/xdg-data",
+			wantConfig: "/tmp/xdg-config",
+			wantCache:  "/tmp/xdg-cache",
+			wantState:  "/tmp/xdg-state",
+		},
+		{
+			name: "all unset falls back to spec defaults",
+			env: map[string]string{
+				"XDG_DATA_HOME":   "",
+				"XDG_CONFIG_HOME": "",
+				"XDG_CACHE_HOME":  "",
+				"XDG_STATE_HOME":  "",
+			},
+			wantData:   filepath.Join(home, ".local", "share"),
+			wantConfig: filepath.Join(home, ".config"),
+			wantCache:  filepath.Join(home, ".cache"),
+			wantState:  filepath.Join(home, ".local", "state"),
+		},
+		{
+			name: "only data home set",
+			env: map[string]string{
+				"XDG_DATA_HOME":   "/tmp/xdg-data",
+				"XDG_CONFIG_HOME": "",
+				"XDG_CACHE_HOME":  "",
+				"XDG_STATE_HOME":  "",
+			},
+			wantData:   "/tmp/xdg-data",
+			wantConfig: filepath.Join(home, ".config"),
+			wantCache:  filepath.Join(home, ".cache"),
+			wantState:  filepath.Join(home, ".local", "state"),
+		},
+		{
+			name: "only state home set",
+			env: map[string]string{
+				"XDG_DATA_HOME":   "",
+				"XDG_CONFIG_HOME": "",
+				"XDG_CACHE_HOME":  "",
+				"XDG_STATE_HOME":  "/tmp/xdg-state",
+			},
+			wantData:   filepath.Join(home, ".local", "share"),
+			wantConfig: filepath.Join(home, ".config"),
+			wantCache:  filepath.Join(home, ".cache"),
+			wantState:  "/tmp/xdg-state",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withEnv(t, tc.env)
+			withEnv(t, map[string]string{"HANZO_AGENTS_LAYOUT": "xdg", "HANZO_AGENTS_HOME": ""})
+
+			dirs, err := GetHanzoAgentsDataDirectories()
+			require.NoError(t, err)
+			require.Equal(t, LayoutXDG, dirs.Layout)
+			require.Equal(t, filepath.Join(tc.wantData, "hanzo-agents"), dirs.DataDir)
+			require.Equal(t, filepath.Join(tc.wantConfig, "hanzo-agents"), dirs.ConfigDir)
+			require.Equal(t, filepath.Join(tc.wantCache, "hanzo-agents", "payloads"), dirs.PayloadsDir)
+			require.Equal(t, filepath.Join(tc.wantState, "hanzo-agents"), dirs.LogsDir)
+		})
+	}
+}
+
+func TestResolveLayout_Override(t *testing.T) {
+	withEnv(t, map[string]string{"HANZO_AGENTS_LAYOUT": "legacy"})
+	require.Equal(t, LayoutLegacy, resolveLayout())
+
+	withEnv(t, map[string]string{"HANZO_AGENTS_LAYOUT": "xdg"})
+	require.Equal(t, LayoutXDG, resolveLayout())
+}
+
+func TestMigrateLegacyToXDG(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("xdg layout is Linux-only for now")
+	}
+
+	legacyHome := t.TempDir()
+	xdgData := t.TempDir()
+	xdgConfig := t.TempDir()
+	xdgCache := t.TempDir()
+	xdgState := t.TempDir()
+
+	withEnv(t, map[string]string{
+		"HANZO_AGENTS_HOME": legacyHome,
+		"XDG_DATA_HOME":     xdgData,
+		"XDG_CONFIG_HOME":   xdgConfig,
+		"XDG_CACHE_HOME":    xdgCache,
+		"XDG_STATE_HOME":    xdgState,
+	})
+
+	// Populate a legacy tree.
+	require.NoError(t, os.MkdirAll(filepath.Join(legacyHome, "data", "keys"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyHome, "data", "keys", "id.key"), []byte("secret"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyHome, "installed.json"), []byte("{}"), 0644))
+
+	withEnv(t, map[string]string{"HANZO_AGENTS_LAYOUT": "xdg"})
+	dirs, err := GetHanzoAgentsDataDirectories()
+	require.NoError(t, err)
+
+	require.NoError(t, MigrateLegacyToXDG(dirs))
+
+	migratedKey := filepath.Join(dirs.KeysDir, "id.key")
+	content, err := os.ReadFile(migratedKey)
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(content))
+
+	migratedRegistry := filepath.Join(dirs.RegistryDir, "installed.json")
+	_, err = os.Stat(migratedRegistry)
+	require.NoError(t, err)
+
+	// Legacy source should be gone, and re-running the migration should be a no-op.
+	_, err = os.Stat(filepath.Join(legacyHome, "data", "keys", "id.key"))
+	require.True(t, os.IsNotExist(err))
+	require.NoError(t, MigrateLegacyToXDG(dirs))
+}
+
+// TestEnsureDataDirectories_MigratesLegacyTree exercises the real
+// EnsureDataDirectories entry point rather than calling MigrateLegacyToXDG
+// directly: it previously ran migration *after* MkdirAll had already
+// created every XDG destination directory, so migrateEntry's
+// don't-clobber-an-existing-destination check silently skipped every
+// subdirectory (only the top-level files happened to still move).
+func TestEnsureDataDirectories_MigratesLegacyTree(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("xdg layout is Linux-only for now")
+	}
+
+	legacyHome := t.TempDir()
+	xdgData := t.TempDir()
+	xdgConfig := t.TempDir()
+	xdgCache := t.TempDir()
+	xdgState := t.TempDir()
+
+	withEnv(t, map[string]string{
+		"HANZO_AGENTS_HOME":   legacyHome,
+		"XDG_DATA_HOME":       xdgData,
+		"XDG_CONFIG_HOME":     xdgConfig,
+		"XDG_CACHE_HOME":      xdgCache,
+		"XDG_STATE_HOME":      xdgState,
+		"HANZO_AGENTS_LAYOUT": "legacy",
+	})
+
+	// Populate a legacy tree under the still-legacy layout, mirroring what
+	// an existing installation looks like before it upgrades.
+	require.NoError(t, os.MkdirAll(filepath.Join(legacyHome, "data", "keys"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyHome, "data", "keys", "id.key"), []byte("secret"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(legacyHome, "data", "vcs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyHome, "data", "vcs", "vc1.json"), []byte("{}"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(legacyHome, "data", "payloads"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyHome, "data", "payloads", "p1.bin"), []byte("payload"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(legacyHome, "logs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyHome, "logs", "app.log"), []byte("log line"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyHome, "installed.json"), []byte("{}"), 0644))
+
+	// Now flip the installation over to xdg and run the real bootstrap path.
+	withEnv(t, map[string]string{"HANZO_AGENTS_LAYOUT": "xdg"})
+	dirs, err := EnsureDataDirectories()
+	require.NoError(t, err)
+	require.Equal(t, LayoutXDG, dirs.Layout)
+
+	keyContent, err := os.ReadFile(filepath.Join(dirs.KeysDir, "id.key"))
+	require.NoError(t, err)
+	require.Equal(t, "secret", string(keyContent))
+
+	vcContent, err := os.ReadFile(filepath.Join(dirs.VCsDir, "vc1.json"))
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(vcContent))
+
+	payloadContent, err := os.ReadFile(filepath.Join(dirs.PayloadsDir, "p1.bin"))
+	require.NoError(t, err)
+	require.Equal(t, "payload", string(payloadContent))
+
+	logContent, err := os.ReadFile(filepath.Join(dirs.LogsDir, "app.log"))
+	require.NoError(t, err)
+	require.Equal(t, "log line", string(logContent))
+
+	_, err = os.Stat(filepath.Join(dirs.RegistryDir, "installed.json"))
+	require.NoError(t, err)
+
+	// Legacy sources should be gone, not merely shadowed by empty XDG dirs.
+	_, err = os.Stat(filepath.Join(legacyHome, "data", "keys", "id.key"))
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(legacyHome, "logs", "app.log"))
+	require.True(t, os.IsNotExist(err))
+}